@@ -0,0 +1,284 @@
+# Alarmas de CloudWatch y dashboard opcional del servicio
+# Las alarmas del ALB (5xx, hosts no saludables, latencia p99) usan el target group principal
+
+locals {
+  alarm_actions = var.alarms != null ? var.alarms.sns_topic_arns : []
+  ok_actions    = try(var.alarms.ok_actions, false) ? local.alarm_actions : []
+
+  # var.service_name en lugar de local.ecs_service.name: el servicio referencia estas alarmas (deployment_alarms)
+  service_alarm_dimensions = {
+    ClusterName = var.cluster_name
+    ServiceName = var.service_name
+  }
+
+  # Las métricas de AWS/ApplicationELB usan el sufijo del ARN (app/<name>/<id> y targetgroup/<name>/<id>)
+  alb_alarm_dimensions = var.alb_load_balancer_arn != null ? {
+    LoadBalancer = regex("loadbalancer/(.+)$", var.alb_load_balancer_arn)[0]
+    TargetGroup  = aws_lb_target_group.webapp["main"].arn_suffix
+  } : null
+
+  create_alb_alarms = var.alarms != null && var.alb_load_balancer_arn != null
+
+  module_alarms = {
+    cpu_high                 = aws_cloudwatch_metric_alarm.cpu_high
+    memory_high              = aws_cloudwatch_metric_alarm.memory_high
+    running_tasks_low        = aws_cloudwatch_metric_alarm.running_tasks_low
+    target_5xx_rate          = aws_cloudwatch_metric_alarm.target_5xx_rate
+    unhealthy_hosts          = aws_cloudwatch_metric_alarm.unhealthy_hosts
+    target_response_time_p99 = aws_cloudwatch_metric_alarm.target_response_time_p99
+  }
+  module_alarm_names = { for name, alarm in local.module_alarms : name => alarm[0].alarm_name if length(alarm) > 0 }
+
+  deployment_alarm_names = var.deployment_alarms != null ? concat(
+    [for name in var.deployment_alarms.module_alarms : local.module_alarm_names[name] if contains(keys(local.module_alarm_names), name)],
+    var.deployment_alarms.alarm_names
+  ) : []
+}
+
+resource "aws_cloudwatch_metric_alarm" "cpu_high" {
+  count = try(var.alarms.cpu_high.enabled, false) ? 1 : 0
+
+  alarm_name          = module.names.names["cpu_high_alarm"]
+  alarm_description   = "CPU utilization of ECS service ${var.service_name} above ${var.alarms.cpu_high.threshold}%"
+  namespace           = "AWS/ECS"
+  metric_name         = "CPUUtilization"
+  dimensions          = local.service_alarm_dimensions
+  statistic           = "Average"
+  period              = var.alarms.cpu_high.period
+  evaluation_periods  = var.alarms.cpu_high.evaluation_periods
+  comparison_operator = "GreaterThanThreshold"
+  threshold           = var.alarms.cpu_high.threshold
+  treat_missing_data  = "notBreaching"
+  alarm_actions       = local.alarm_actions
+  ok_actions          = local.ok_actions
+
+  tags = var.common_tags
+}
+
+resource "aws_cloudwatch_metric_alarm" "memory_high" {
+  count = try(var.alarms.memory_high.enabled, false) ? 1 : 0
+
+  alarm_name          = module.names.names["memory_high_alarm"]
+  alarm_description   = "Memory utilization of ECS service ${var.service_name} above ${var.alarms.memory_high.threshold}%"
+  namespace           = "AWS/ECS"
+  metric_name         = "MemoryUtilization"
+  dimensions          = local.service_alarm_dimensions
+  statistic           = "Average"
+  period              = var.alarms.memory_high.period
+  evaluation_periods  = var.alarms.memory_high.evaluation_periods
+  comparison_operator = "GreaterThanThreshold"
+  threshold           = var.alarms.memory_high.threshold
+  treat_missing_data  = "notBreaching"
+  alarm_actions       = local.alarm_actions
+  ok_actions          = local.ok_actions
+
+  tags = var.common_tags
+}
+
+# Requiere Container Insights en el cluster (namespace ECS/ContainerInsights)
+# Compara con min_capacity y no con DesiredTaskCount, que supera a las tareas en ejecución en cada despliegue
+# y cada scale-out. Maximum: solo salta si en ningún momento del periodo hubo min_capacity tareas.
+# Sin min_capacity (0) no hay mínimo que vigilar y no se crea
+resource "aws_cloudwatch_metric_alarm" "running_tasks_low" {
+  count = try(var.alarms.running_tasks_low.enabled, false) && var.autoscaling_config.min_capacity > 0 ? 1 : 0
+
+  alarm_name          = module.names.names["running_tasks_low_alarm"]
+  alarm_description   = "Running tasks of ECS service ${var.service_name} below the autoscaling minimum (${var.autoscaling_config.min_capacity})"
+  namespace           = "ECS/ContainerInsights"
+  metric_name         = "RunningTaskCount"
+  dimensions          = local.service_alarm_dimensions
+  statistic           = "Maximum"
+  period              = var.alarms.running_tasks_low.period
+  evaluation_periods  = var.alarms.running_tasks_low.evaluation_periods
+  comparison_operator = "LessThanThreshold"
+  threshold           = var.autoscaling_config.min_capacity
+  treat_missing_data  = "notBreaching"
+  alarm_actions       = local.alarm_actions
+  ok_actions          = local.ok_actions
+
+  tags = var.common_tags
+}
+
+resource "aws_cloudwatch_metric_alarm" "target_5xx_rate" {
+  count = local.create_alb_alarms && try(var.alarms.target_5xx_rate.enabled, false) ? 1 : 0
+
+  alarm_name          = module.names.names["target_5xx_rate_alarm"]
+  alarm_description   = "Target 5xx responses of ECS service ${var.service_name} above ${var.alarms.target_5xx_rate.threshold}% of requests"
+  evaluation_periods  = var.alarms.target_5xx_rate.evaluation_periods
+  comparison_operator = "GreaterThanThreshold"
+  threshold           = var.alarms.target_5xx_rate.threshold
+  treat_missing_data  = "notBreaching"
+  alarm_actions       = local.alarm_actions
+  ok_actions          = local.ok_actions
+
+  metric_query {
+    id          = "rate"
+    expression  = "IF(requests > 0, 100 * errors / requests, 0)"
+    label       = "Target 5xx rate (%)"
+    return_data = true
+  }
+
+  metric_query {
+    id = "errors"
+
+    metric {
+      namespace   = "AWS/ApplicationELB"
+      metric_name = "HTTPCode_Target_5XX_Count"
+      dimensions  = local.alb_alarm_dimensions
+      stat        = "Sum"
+      period      = var.alarms.target_5xx_rate.period
+    }
+  }
+
+  metric_query {
+    id = "requests"
+
+    metric {
+      namespace   = "AWS/ApplicationELB"
+      metric_name = "RequestCount"
+      dimensions  = local.alb_alarm_dimensions
+      stat        = "Sum"
+      period      = var.alarms.target_5xx_rate.period
+    }
+  }
+
+  tags = var.common_tags
+}
+
+resource "aws_cloudwatch_metric_alarm" "unhealthy_hosts" {
+  count = local.create_alb_alarms && try(var.alarms.unhealthy_hosts.enabled, false) ? 1 : 0
+
+  alarm_name          = module.names.names["unhealthy_hosts_alarm"]
+  alarm_description   = "Unhealthy targets of ECS service ${var.service_name} above ${var.alarms.unhealthy_hosts.threshold}"
+  namespace           = "AWS/ApplicationELB"
+  metric_name         = "UnHealthyHostCount"
+  dimensions          = local.alb_alarm_dimensions
+  statistic           = "Maximum"
+  period              = var.alarms.unhealthy_hosts.period
+  evaluation_periods  = var.alarms.unhealthy_hosts.evaluation_periods
+  comparison_operator = "GreaterThanThreshold"
+  threshold           = var.alarms.unhealthy_hosts.threshold
+  treat_missing_data  = "notBreaching"
+  alarm_actions       = local.alarm_actions
+  ok_actions          = local.ok_actions
+
+  tags = var.common_tags
+}
+
+resource "aws_cloudwatch_metric_alarm" "target_response_time_p99" {
+  count = local.create_alb_alarms && try(var.alarms.target_response_time_p99.enabled, false) ? 1 : 0
+
+  alarm_name          = module.names.names["target_response_time_p99_alarm"]
+  alarm_description   = "p99 target response time of ECS service ${var.service_name} above ${var.alarms.target_response_time_p99.threshold}s"
+  namespace           = "AWS/ApplicationELB"
+  metric_name         = "TargetResponseTime"
+  dimensions          = local.alb_alarm_dimensions
+  extended_statistic  = "p99"
+  period              = var.alarms.target_response_time_p99.period
+  evaluation_periods  = var.alarms.target_response_time_p99.evaluation_periods
+  comparison_operator = "GreaterThanThreshold"
+  threshold           = var.alarms.target_response_time_p99.threshold
+  treat_missing_data  = "notBreaching"
+  alarm_actions       = local.alarm_actions
+  ok_actions          = local.ok_actions
+
+  tags = var.common_tags
+}
+
+resource "aws_cloudwatch_dashboard" "webapp" {
+  count = try(var.alarms.dashboard, false) ? 1 : 0
+
+  dashboard_name = module.names.names["dashboard"]
+  dashboard_body = jsonencode({
+    widgets = concat(
+      [
+        {
+          type   = "metric"
+          x      = 0
+          y      = 0
+          width  = 12
+          height = 6
+          properties = {
+            title  = "CPU / Memory (%)"
+            region = data.aws_region.current.name
+            stat   = "Average"
+            period = 60
+            metrics = [
+              ["AWS/ECS", "CPUUtilization", "ClusterName", var.cluster_name, "ServiceName", local.ecs_service.name],
+              ["AWS/ECS", "MemoryUtilization", "ClusterName", var.cluster_name, "ServiceName", local.ecs_service.name],
+            ]
+          }
+        },
+        {
+          type   = "metric"
+          x      = 12
+          y      = 0
+          width  = 12
+          height = 6
+          properties = {
+            title  = "Tasks (running / desired)"
+            region = data.aws_region.current.name
+            stat   = "Maximum"
+            period = 60
+            metrics = [
+              ["ECS/ContainerInsights", "RunningTaskCount", "ClusterName", var.cluster_name, "ServiceName", local.ecs_service.name],
+              ["ECS/ContainerInsights", "DesiredTaskCount", "ClusterName", var.cluster_name, "ServiceName", local.ecs_service.name],
+            ]
+          }
+        },
+      ],
+      var.alb_load_balancer_arn != null ? [
+        {
+          type   = "metric"
+          x      = 0
+          y      = 6
+          width  = 8
+          height = 6
+          properties = {
+            title  = "Requests / Target 5xx"
+            region = data.aws_region.current.name
+            stat   = "Sum"
+            period = 60
+            metrics = [
+              ["AWS/ApplicationELB", "RequestCount", "LoadBalancer", local.alb_alarm_dimensions.LoadBalancer, "TargetGroup", local.alb_alarm_dimensions.TargetGroup],
+              ["AWS/ApplicationELB", "HTTPCode_Target_5XX_Count", "LoadBalancer", local.alb_alarm_dimensions.LoadBalancer, "TargetGroup", local.alb_alarm_dimensions.TargetGroup],
+            ]
+          }
+        },
+        {
+          type   = "metric"
+          x      = 8
+          y      = 6
+          width  = 8
+          height = 6
+          properties = {
+            title  = "Target response time p99 (s)"
+            region = data.aws_region.current.name
+            stat   = "p99"
+            period = 60
+            metrics = [
+              ["AWS/ApplicationELB", "TargetResponseTime", "LoadBalancer", local.alb_alarm_dimensions.LoadBalancer, "TargetGroup", local.alb_alarm_dimensions.TargetGroup],
+            ]
+          }
+        },
+        {
+          type   = "metric"
+          x      = 16
+          y      = 6
+          width  = 8
+          height = 6
+          properties = {
+            title  = "Healthy / unhealthy targets"
+            region = data.aws_region.current.name
+            stat   = "Maximum"
+            period = 60
+            metrics = [
+              ["AWS/ApplicationELB", "HealthyHostCount", "LoadBalancer", local.alb_alarm_dimensions.LoadBalancer, "TargetGroup", local.alb_alarm_dimensions.TargetGroup],
+              ["AWS/ApplicationELB", "UnHealthyHostCount", "LoadBalancer", local.alb_alarm_dimensions.LoadBalancer, "TargetGroup", local.alb_alarm_dimensions.TargetGroup],
+            ]
+          }
+        },
+      ] : []
+    )
+  })
+}
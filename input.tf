@@ -6,11 +6,42 @@ variable "cluster_name" {
 variable "service_name" {
   description = "Name of the ECS service"
   type        = string
+
+  validation {
+    condition     = can(regex("^[a-zA-Z0-9_-]{1,255}$", var.service_name))
+    error_message = "service_name must be 1-255 letters, numbers, hyphens or underscores"
+  }
+}
+
+variable "name_prefix" {
+  description = <<-EOT
+    Base name for the resources created by the module (target groups, IAM roles, security group, scaling policies).
+    Defaults to service_name. Names that exceed the AWS limit (32 for target groups, 64 for IAM roles) are truncated
+    with a deterministic hash suffix
+  EOT
+  type        = string
+  default     = null
+
+  validation {
+    condition     = var.name_prefix == null || can(regex("^[a-zA-Z0-9][a-zA-Z0-9_-]{0,199}$", var.name_prefix))
+    error_message = "name_prefix must start with a letter or number and contain up to 200 letters, numbers, hyphens or underscores"
+  }
 }
 
 variable "docker_image" {
-  description = "Docker image in ECR"
+  description = "Docker image repository (ECR or any other registry, e.g. ghcr.io/org/app). Use repository_credentials_secret_arn for private non-ECR registries"
+  type        = string
+}
+
+variable "repository_credentials_secret_arn" {
+  description = "ARN of the Secrets Manager secret with the username/password used to pull the image from a private non-ECR registry (repositoryCredentials)"
   type        = string
+  default     = null
+
+  validation {
+    condition     = var.repository_credentials_secret_arn == null || can(regex("^arn:aws:secretsmanager:", var.repository_credentials_secret_arn))
+    error_message = "repository_credentials_secret_arn must be a valid ARN starting with 'arn:aws:secretsmanager:'"
+  }
 }
 
 variable "container_port" {
@@ -18,6 +49,62 @@ variable "container_port" {
   type        = number
 }
 
+variable "additional_ports" {
+  description = "Additional ports exposed by the container (e.g. admin/metrics or gRPC). Each port can have its own ALB target group, health check and listener rules. container_port remains the main port"
+  type = list(object({
+    name     = string
+    port     = number
+    protocol = optional(string, "tcp")
+    target_group = optional(object({
+      protocol         = optional(string, "HTTP")
+      protocol_version = optional(string, "HTTP1")
+      listener_arn     = optional(string)
+      health_check = optional(object({
+        path                = string
+        interval            = number
+        timeout             = number
+        healthy_threshold   = number
+        unhealthy_threshold = number
+        matcher             = string
+      }))
+      listener_rules = list(object({
+        priority      = number
+        path_patterns = optional(list(string))
+        host_headers  = optional(list(string))
+      }))
+    }))
+  }))
+  default = []
+
+  validation {
+    condition     = length(distinct([for port in var.additional_ports : port.name])) == length(var.additional_ports) && length(setintersection([for port in var.additional_ports : port.name], ["main", "nlb"])) == 0
+    error_message = "additional_ports names must be unique and cannot be 'main' (reserved for container_port) or 'nlb' (reserved for the NLB target group)"
+  }
+
+  validation {
+    condition     = alltrue([for port in var.additional_ports : can(regex("^[a-zA-Z0-9-]{1,16}$", port.name))])
+    error_message = "additional_ports names must be 1-16 letters, numbers or hyphens (they are part of the target group name)"
+  }
+
+  validation {
+    condition     = alltrue([for port in var.additional_ports : contains(["tcp", "udp"], port.protocol)])
+    error_message = "additional_ports.protocol must be 'tcp' or 'udp'"
+  }
+
+  validation {
+    condition = alltrue([
+      for port in var.additional_ports :
+      port.target_group == null || try(
+        contains(["HTTP", "HTTPS"], port.target_group.protocol) &&
+        contains(["HTTP1", "HTTP2", "GRPC"], port.target_group.protocol_version) &&
+        length(port.target_group.listener_rules) > 0,
+        false
+      )
+    ])
+    error_message = "additional_ports.target_group.protocol must be 'HTTP' or 'HTTPS', protocol_version must be 'HTTP1', 'HTTP2' or 'GRPC', and at least one listener_rule must be provided"
+  }
+}
+
 variable "task_cpu" {
   description = "Amount of CPU for the ECS task (in CPU units)"
   type        = string
@@ -29,10 +116,33 @@ variable "task_memory" {
 }
 
 variable "subnet_ids" {
-  description = "IDs of private subnets for ECS tasks. IMPORTANT: Must be private subnets as tasks are configured without public IPs (assign_public_ip = false) and need to access the internet through a NAT Gateway to download Docker images"
+  description = "IDs of the subnets for ECS tasks. With assign_public_ip = false (default) they must be private subnets with internet access through a NAT Gateway to download Docker images. With assign_public_ip = true they must be public subnets with a route to an Internet Gateway"
   type        = list(string)
 }
 
+variable "assign_public_ip" {
+  description = "Assign a public IP to the ECS tasks. Only for public subnets in environments without NAT Gateway. Every ingress rule of the service security group becomes reachable on the task public IP"
+  type        = bool
+  default     = false
+}
+
+variable "availability_zone_rebalancing" {
+  description = "Let ECS move tasks to rebalance them across the Availability Zones of subnet_ids after an AZ becomes unbalanced (e.g. after an outage or scale-in). null leaves the service setting unmanaged (AWS default)"
+  type        = bool
+  default     = null
+}
+
+variable "min_availability_zones" {
+  description = "Minimum number of distinct Availability Zones that subnet_ids must span. Fargate spreads tasks across the AZs of the subnets, so a value of 2 or more guarantees the service can survive the loss of one AZ"
+  type        = number
+  default     = 1
+
+  validation {
+    condition     = var.min_availability_zones >= 1 && var.min_availability_zones <= 6 && floor(var.min_availability_zones) == var.min_availability_zones
+    error_message = "min_availability_zones must be an integer between 1 and 6"
+  }
+}
+
 variable "vpc_cidr_block" {
   description = "CIDR block of the VPC"
   type        = string
@@ -43,6 +153,63 @@ variable "vpc_id" {
   type        = string
 }
 
+variable "allow_vpc_ingress" {
+  description = "Allow traffic from the whole VPC (vpc_cidr_block) to the container port. Set to false and use security_group_ingress_rules to restrict the sources"
+  type        = bool
+  default     = true
+}
+
+variable "security_group_ingress_rules" {
+  description = "Additional ingress rules for the ECS service security group. Each rule must set exactly one of source_security_group_id, prefix_list_ids or cidr_blocks. Ports default to container_port"
+  type = list(object({
+    description              = string
+    source_security_group_id = optional(string)
+    prefix_list_ids          = optional(list(string))
+    cidr_blocks              = optional(list(string))
+    from_port                = optional(number)
+    to_port                  = optional(number)
+    protocol                 = optional(string, "tcp")
+  }))
+  default = []
+
+  validation {
+    condition = alltrue([
+      for rule in var.security_group_ingress_rules :
+      length(compact([
+        rule.source_security_group_id,
+        rule.prefix_list_ids != null ? "prefix_list_ids" : null,
+        rule.cidr_blocks != null ? "cidr_blocks" : null
+      ])) == 1
+    ])
+    error_message = "Each security_group_ingress_rules entry must set exactly one of source_security_group_id, prefix_list_ids or cidr_blocks"
+  }
+
+  validation {
+    condition     = length(distinct([for rule in var.security_group_ingress_rules : rule.description])) == length(var.security_group_ingress_rules)
+    error_message = "security_group_ingress_rules descriptions must be unique"
+  }
+}
+
+variable "security_group_egress_rules" {
+  description = "Egress rules that replace the default allow-all egress of the ECS service security group. If null, all outbound traffic is allowed. Tasks still need HTTPS egress to pull images and reach AWS APIs"
+  type = list(object({
+    description     = string
+    from_port       = number
+    to_port         = number
+    protocol        = string
+    cidr_blocks     = optional(list(string))
+    prefix_list_ids = optional(list(string))
+    security_groups = optional(list(string))
+  }))
+  default = null
+}
+
+variable "additional_security_group_ids" {
+  description = "Existing security group IDs to attach to the ECS tasks in addition to the module's security group"
+  type        = list(string)
+  default     = []
+}
+
 variable "alb_load_balancer_arn" {
   description = "ARN of the ALB load balancer (HTTP or HTTPS). Required if using ALB."
   type        = string
@@ -61,6 +228,57 @@ variable "alb_security_group_id" {
   default     = null
 }
 
+variable "nlb" {
+  description = <<-EOT
+    Network Load Balancer configuration for TCP/UDP/TLS services (MQTT, raw TCP, etc.).
+    Creates a target group for container_port and, optionally, its listener on the NLB.
+    Ingress to the tasks is allowed from the NLB security group and/or client_cidr_blocks (requires preserve_client_ip).
+  EOT
+  type = object({
+    load_balancer_arn  = string
+    security_group_id  = optional(string)
+    protocol           = optional(string, "TCP")
+    preserve_client_ip = optional(bool)
+    client_cidr_blocks = optional(list(string), [])
+    listener = optional(object({
+      port            = number
+      protocol        = optional(string)
+      certificate_arn = optional(string)
+      ssl_policy      = optional(string)
+    }))
+    health_check = optional(object({
+      protocol            = optional(string, "TCP")
+      path                = optional(string)
+      matcher             = optional(string)
+      interval            = optional(number, 30)
+      timeout             = optional(number)
+      healthy_threshold   = optional(number, 3)
+      unhealthy_threshold = optional(number, 3)
+    }), {})
+  })
+  default = null
+
+  validation {
+    condition     = var.nlb == null || try(contains(["TCP", "UDP", "TCP_UDP", "TLS"], var.nlb.protocol), false)
+    error_message = "nlb.protocol must be 'TCP', 'UDP', 'TCP_UDP' or 'TLS'"
+  }
+
+  validation {
+    condition     = var.nlb == null || try(contains(["TCP", "HTTP", "HTTPS"], var.nlb.health_check.protocol), false)
+    error_message = "nlb.health_check.protocol must be 'TCP', 'HTTP' or 'HTTPS'"
+  }
+
+  validation {
+    condition     = var.nlb == null || try(var.nlb.health_check.protocol == "TCP" || var.nlb.health_check.path != null, false)
+    error_message = "nlb.health_check.path is required when nlb.health_check.protocol is 'HTTP' or 'HTTPS'"
+  }
+
+  validation {
+    condition     = var.nlb == null || try(var.nlb.listener == null || coalesce(var.nlb.listener.protocol, var.nlb.protocol) != "TLS" || var.nlb.listener.certificate_arn != null, false)
+    error_message = "nlb.listener.certificate_arn is required for TLS listeners"
+  }
+}
+
 variable "service_discovery" {
   description = "ID of the Service Discovery namespace"
   type = object({
@@ -83,6 +301,20 @@ variable "environment_variables" {
   default = []
 }
 
+variable "environment_files" {
+  description = "List of S3 object ARNs of .env files to load into the container (environmentFiles). Useful for large sets of environment variables."
+  type        = list(string)
+  default     = []
+
+  validation {
+    condition = alltrue([
+      for file in var.environment_files :
+      can(regex("^arn:aws[a-zA-Z-]*:s3:::[^/]+/.+\\.env$", file))
+    ])
+    error_message = "All environment_files must be S3 object ARNs (arn:<partition>:s3:::bucket/key) with the .env extension"
+  }
+}
+
 variable "secret_variables" {
   description = "Secrets to pass to the container. Each secret must have 'name' and 'valueFrom' (ARN of SSM Parameter Store or Secrets Manager)"
   type = list(object({
@@ -101,7 +333,7 @@ variable "secret_variables" {
 }
 
 variable "health_check" {
-  description = "Target Group health check configuration"
+  description = "Target Group health check configuration. matcher takes HTTP codes (200-499) or, with target_group_protocol_version = GRPC, gRPC codes (0-99)"
   type = object({
     path                = string
     interval            = number
@@ -122,6 +354,25 @@ variable "listener_rules" {
   default = []
 }
 
+variable "route53" {
+  description = <<-EOT
+    Route 53 alias records for the listener rules host_headers (main port and additional_ports).
+    Creates A alias records pointing at the ALB in zone_id, plus AAAA if the ALB is dualstack. Host headers with '?' wildcards are skipped.
+  EOT
+  type = object({
+    zone_id                = string
+    create_aliases         = optional(bool, true)
+    evaluate_target_health = optional(bool, true)
+  })
+  default = null
+}
+
+variable "manage_desired_count_via_autoscaling" {
+  description = "Set desired_count (autoscaling_config.min_capacity) only when the service is created and ignore later drift, so applies after a scale-out don't shed capacity. WARNING: toggling it on an existing stack (either way) destroys and recreates the ECS service, with downtime, unless a moved block is added (see README)"
+  type        = bool
+  default     = false
+}
+
 variable "autoscaling_config" {
   description = <<-EOT
     Auto scaling configuration.
@@ -182,12 +433,93 @@ variable "task_policy_json" {
   default     = null
 }
 
+variable "target_group_protocol" {
+  description = "Protocol used by the ALB to reach the container: HTTP, or HTTPS when the container terminates TLS itself"
+  type        = string
+  default     = "HTTP"
+
+  validation {
+    condition     = contains(["HTTP", "HTTPS"], var.target_group_protocol)
+    error_message = "target_group_protocol must be 'HTTP' or 'HTTPS'"
+  }
+}
+
+variable "target_group_protocol_version" {
+  description = "Protocol version of the Target Group: HTTP1, HTTP2 or GRPC. GRPC requires an HTTPS listener and a health_check.matcher with gRPC status codes (e.g. '0' or '0-99')"
+  type        = string
+  default     = "HTTP1"
+
+  validation {
+    condition     = contains(["HTTP1", "HTTP2", "GRPC"], var.target_group_protocol_version)
+    error_message = "target_group_protocol_version must be 'HTTP1', 'HTTP2' or 'GRPC'"
+  }
+}
+
 variable "target_group_deregistration_delay" {
   description = "Amount of time for Elastic Load Balancing to wait before changing the state of a deregistering target from draining to unused"
   type        = number
   default     = 300
 }
 
+variable "target_group_config" {
+  description = <<-EOT
+    Tuning for the ALB target groups (main and additional_ports):
+    - stickiness: lb_cookie (cookie_duration) or app_cookie (cookie_name, cookie_duration)
+    - slow_start: seconds (30-900) to ramp up traffic to new targets, 0 disables it. Only with round_robin
+    - load_balancing_algorithm_type: round_robin, least_outstanding_requests or weighted_random
+    - load_balancing_anomaly_mitigation: "on" or "off", only with weighted_random
+  EOT
+  type = object({
+    slow_start                        = optional(number, 0)
+    load_balancing_algorithm_type     = optional(string, "round_robin")
+    load_balancing_anomaly_mitigation = optional(string)
+    stickiness = optional(object({
+      type            = string
+      cookie_duration = optional(number, 86400)
+      cookie_name     = optional(string)
+    }))
+  })
+  default = {}
+
+  validation {
+    condition     = contains(["round_robin", "least_outstanding_requests", "weighted_random"], var.target_group_config.load_balancing_algorithm_type)
+    error_message = "target_group_config.load_balancing_algorithm_type must be 'round_robin', 'least_outstanding_requests' or 'weighted_random'"
+  }
+
+  validation {
+    condition = var.target_group_config.load_balancing_anomaly_mitigation == null || (
+      var.target_group_config.load_balancing_algorithm_type == "weighted_random" &&
+      contains(["on", "off"], coalesce(var.target_group_config.load_balancing_anomaly_mitigation, "off"))
+    )
+    error_message = "target_group_config.load_balancing_anomaly_mitigation must be 'on' or 'off' and requires load_balancing_algorithm_type = 'weighted_random'"
+  }
+
+  validation {
+    condition     = var.target_group_config.slow_start == 0 || (var.target_group_config.slow_start >= 30 && var.target_group_config.slow_start <= 900)
+    error_message = "target_group_config.slow_start must be 0 (disabled) or between 30 and 900 seconds"
+  }
+
+  validation {
+    condition     = var.target_group_config.slow_start == 0 || var.target_group_config.load_balancing_algorithm_type == "round_robin"
+    error_message = "target_group_config.slow_start is only supported with load_balancing_algorithm_type = 'round_robin'"
+  }
+
+  validation {
+    condition     = var.target_group_config.stickiness == null || try(contains(["lb_cookie", "app_cookie"], var.target_group_config.stickiness.type), false)
+    error_message = "target_group_config.stickiness.type must be 'lb_cookie' or 'app_cookie'"
+  }
+
+  validation {
+    condition     = var.target_group_config.stickiness == null || try(var.target_group_config.stickiness.type != "app_cookie" || var.target_group_config.stickiness.cookie_name != null, false)
+    error_message = "target_group_config.stickiness.cookie_name is required for app_cookie stickiness"
+  }
+
+  validation {
+    condition     = var.target_group_config.stickiness == null || try(var.target_group_config.stickiness.cookie_duration >= 1 && var.target_group_config.stickiness.cookie_duration <= 604800, false)
+    error_message = "target_group_config.stickiness.cookie_duration must be between 1 and 604800 seconds"
+  }
+}
+
 variable "force_new_deployment" {
   description = "Force a new deployment of the service when set to true"
   type        = bool
@@ -208,17 +540,213 @@ variable "enable_deployment_circuit_breaker" {
   default     = false
 }
 
+variable "alarms" {
+  description = <<-EOT
+    CloudWatch alarms for the service, notifying sns_topic_arns on ALARM (and OK if ok_actions = true):
+    - cpu_high / memory_high: service CPUUtilization / MemoryUtilization above threshold (%)
+    - running_tasks_low: RunningTaskCount below autoscaling_config.min_capacity for the whole period (requires Container Insights
+      on the cluster, not created with min_capacity = 0)
+    - target_5xx_rate: HTTPCode_Target_5XX_Count / RequestCount above threshold (%) (ALB only)
+    - unhealthy_hosts: UnHealthyHostCount above threshold (ALB only)
+    - target_response_time_p99: p99 TargetResponseTime above threshold (seconds) (ALB only)
+    Each alarm can be disabled with enabled = false. dashboard = true creates a CloudWatch dashboard for the service.
+  EOT
+  type = object({
+    sns_topic_arns = list(string)
+    ok_actions     = optional(bool, true)
+    dashboard      = optional(bool, false)
+    cpu_high = optional(object({
+      enabled            = optional(bool, true)
+      threshold          = optional(number, 80)
+      period             = optional(number, 60)
+      evaluation_periods = optional(number, 5)
+    }), {})
+    memory_high = optional(object({
+      enabled            = optional(bool, true)
+      threshold          = optional(number, 80)
+      period             = optional(number, 60)
+      evaluation_periods = optional(number, 5)
+    }), {})
+    running_tasks_low = optional(object({
+      enabled            = optional(bool, true)
+      period             = optional(number, 60)
+      evaluation_periods = optional(number, 5)
+    }), {})
+    target_5xx_rate = optional(object({
+      enabled            = optional(bool, true)
+      threshold          = optional(number, 5)
+      period             = optional(number, 60)
+      evaluation_periods = optional(number, 3)
+    }), {})
+    unhealthy_hosts = optional(object({
+      enabled            = optional(bool, true)
+      threshold          = optional(number, 0)
+      period             = optional(number, 60)
+      evaluation_periods = optional(number, 3)
+    }), {})
+    target_response_time_p99 = optional(object({
+      enabled            = optional(bool, true)
+      threshold          = optional(number, 2)
+      period             = optional(number, 60)
+      evaluation_periods = optional(number, 5)
+    }), {})
+  })
+  default = null
+
+  validation {
+    condition     = var.alarms == null || try(length(var.alarms.sns_topic_arns) > 0, false)
+    error_message = "alarms.sns_topic_arns must contain at least one SNS topic ARN"
+  }
+}
+
+variable "deployment_alarms" {
+  description = <<-EOT
+    CloudWatch alarms monitored by ECS during deployments. If any of them goes into ALARM the deployment fails
+    and, with rollback = true, ECS rolls back to the previous task definition.
+    - module_alarms: alarms created by this module (keys of the alarms input: target_5xx_rate, unhealthy_hosts, ...).
+      running_tasks_low is not allowed: tasks being replaced during the deployment would roll it back
+    - alarm_names: names of existing CloudWatch alarms
+  EOT
+  type = object({
+    module_alarms = optional(list(string), ["target_5xx_rate", "unhealthy_hosts"])
+    alarm_names   = optional(list(string), [])
+    rollback      = optional(bool, true)
+  })
+  default = null
+
+  validation {
+    condition = var.deployment_alarms == null || try(alltrue([
+      for name in var.deployment_alarms.module_alarms :
+      contains(["cpu_high", "memory_high", "target_5xx_rate", "unhealthy_hosts", "target_response_time_p99"], name)
+    ]), false)
+    error_message = "deployment_alarms.module_alarms must only contain cpu_high, memory_high, target_5xx_rate, unhealthy_hosts or target_response_time_p99 (running_tasks_low would roll back deployments)"
+  }
+
+  validation {
+    condition     = var.deployment_alarms == null || try(length(var.deployment_alarms.module_alarms) + length(var.deployment_alarms.alarm_names) > 0, false)
+    error_message = "deployment_alarms requires at least one alarm in module_alarms or alarm_names"
+  }
+}
+
+variable "scheduled_tasks" {
+  description = <<-EOT
+    EventBridge Scheduler schedules that run the service task definition (same image and configuration) as one-off tasks,
+    with the module subnets and security group:
+    - name: schedule name suffix (1-20 letters, numbers, hyphens or underscores)
+    - schedule_expression: cron(...), rate(...) or at(...)
+    - schedule_expression_timezone: timezone for cron expressions (default: UTC)
+    - command: container command override (default: the container command)
+    - task_count: number of tasks per run (default: 1)
+    - enabled: ENABLED/DISABLED state of the schedule (default: true)
+  EOT
+  type = list(object({
+    name                         = string
+    schedule_expression          = string
+    schedule_expression_timezone = optional(string, "UTC")
+    command                      = optional(list(string))
+    task_count                   = optional(number, 1)
+    enabled                      = optional(bool, true)
+  }))
+  default = []
+
+  validation {
+    condition     = alltrue([for task in var.scheduled_tasks : can(regex("^[a-zA-Z0-9_-]{1,20}$", task.name))]) && length(distinct([for task in var.scheduled_tasks : task.name])) == length(var.scheduled_tasks)
+    error_message = "scheduled_tasks names must be unique and contain 1-20 letters, numbers, hyphens or underscores"
+  }
+
+  validation {
+    condition     = alltrue([for task in var.scheduled_tasks : can(regex("^(cron|rate|at)\\(.+\\)$", task.schedule_expression))])
+    error_message = "scheduled_tasks.schedule_expression must be a cron(...), rate(...) or at(...) expression"
+  }
+
+  validation {
+    condition     = alltrue([for task in var.scheduled_tasks : task.task_count >= 1 && task.task_count <= 10])
+    error_message = "scheduled_tasks.task_count must be between 1 and 10"
+  }
+}
+
+variable "migration" {
+  description = <<-EOT
+    One-off task run with the service task definition before the service rolls out a new revision (e.g. database migrations):
+    - command: container command override for the migration
+    - timeout_seconds: maximum time to wait for the task to stop (default: 900)
+    The apply fails, without updating the service, unless the container exits with code 0. Requires the AWS CLI where Terraform runs.
+    The CLI uses the local credentials, not the provider's assume_role, profile or endpoints: they must belong to the provider's
+    account (checked before running the task) and be allowed to run it.
+  EOT
+  type = object({
+    command         = list(string)
+    timeout_seconds = optional(number, 900)
+  })
+  default = null
+
+  validation {
+    condition     = var.migration == null || try(length(var.migration.command) > 0, false)
+    error_message = "migration.command must not be empty"
+  }
+
+  validation {
+    condition     = var.migration == null || try(var.migration.timeout_seconds >= 60 && var.migration.timeout_seconds <= 7200, false)
+    error_message = "migration.timeout_seconds must be between 60 and 7200"
+  }
+}
+
 variable "cloudwatch_log_group_name" {
-  description = "Full name of the CloudWatch Log Group to use (e.g. /ecs/service-name)"
+  description = "Full name of the CloudWatch Log Group to use (e.g. /ecs/service-name). Must already exist unless create_log_group = true"
   type        = string
 }
 
+variable "create_log_group" {
+  description = "Create the log group cloudwatch_log_group_name in the module (with log_group_retention_in_days, log_group_kms_key_id and log_group_class) instead of using an existing one"
+  type        = bool
+  default     = false
+}
+
+variable "log_group_retention_in_days" {
+  description = "Retention of the module log group in days (0 = never expire). Only with create_log_group = true"
+  type        = number
+  default     = 30
+
+  validation {
+    condition     = contains([0, 1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1096, 1827, 2192, 2557, 2922, 3288, 3653], var.log_group_retention_in_days)
+    error_message = "log_group_retention_in_days must be one of the values supported by CloudWatch Logs (0, 1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1096, 1827, 2192, 2557, 2922, 3288, 3653)"
+  }
+}
+
+variable "log_group_kms_key_id" {
+  description = "ARN of the KMS key used to encrypt the module log group. The key policy must allow the logs.<region>.amazonaws.com service. Only with create_log_group = true"
+  type        = string
+  default     = null
+
+  validation {
+    condition     = var.log_group_kms_key_id == null || can(regex("^arn:aws[a-z-]*:kms:", var.log_group_kms_key_id))
+    error_message = "log_group_kms_key_id must be a KMS key ARN"
+  }
+}
+
+variable "log_group_class" {
+  description = "Log class of the module log group: STANDARD or INFREQUENT_ACCESS. Only with create_log_group = true"
+  type        = string
+  default     = "STANDARD"
+
+  validation {
+    condition     = contains(["STANDARD", "INFREQUENT_ACCESS"], var.log_group_class)
+    error_message = "log_group_class must be 'STANDARD' or 'INFREQUENT_ACCESS'"
+  }
+}
+
 variable "image_tag" {
   description = "Image tag"
   type        = string
   default     = "latest"
 }
 
+variable "resolve_image_digest" {
+  description = "Resolve image_tag to its immutable sha256 digest at plan time and render the image as docker_image@digest. Only supported for ECR images (<account>.dkr.ecr.<region>.amazonaws.com/<repository>)"
+  type        = bool
+  default     = false
+}
+
 variable "container_command" {
   description = "Command to override the default CMD from the Dockerfile. If null, uses the default CMD from the image."
   type        = list(string)
@@ -1,3 +1,154 @@
+# Nombres de los recursos dentro de los límites de AWS (32 para target groups, 64 para roles IAM, etc.)
+module "names" {
+  source = "./modules/naming"
+
+  name = local.name_base
+  resources = merge(
+    {
+      target_group_main                = { suffix = "-tg", max_length = 32, hyphens_only = true }
+      target_group_nlb                 = { suffix = "-nlb-tg", max_length = 32, hyphens_only = true }
+      execution_role                   = { suffix = "-execution-role", max_length = 64 }
+      execution_secrets_policy         = { suffix = "-execution-secrets-policy", max_length = 128 }
+      execution_env_files_policy       = { suffix = "-execution-env-files-policy", max_length = 128 }
+      task_role                        = { suffix = "-role", max_length = 64 }
+      task_policy                      = { suffix = "-policy", max_length = 128 }
+      security_group                   = { suffix = "-sg", max_length = 255 }
+      cpu_scaling_policy               = { prefix = "cpu-scaling-policy-", max_length = 256 }
+      memory_scaling_policy            = { prefix = "memory-scaling-policy-", max_length = 256 }
+      alb_request_count_scaling_policy = { prefix = "alb-request-count-scaling-policy-", max_length = 256 }
+      cpu_high_alarm                   = { suffix = "-cpu-high", max_length = 255 }
+      memory_high_alarm                = { suffix = "-memory-high", max_length = 255 }
+      running_tasks_low_alarm          = { suffix = "-running-tasks-low", max_length = 255 }
+      target_5xx_rate_alarm            = { suffix = "-target-5xx-rate", max_length = 255 }
+      unhealthy_hosts_alarm            = { suffix = "-unhealthy-hosts", max_length = 255 }
+      target_response_time_p99_alarm   = { suffix = "-target-response-time-p99", max_length = 255 }
+      dashboard                        = { suffix = "-dashboard", max_length = 255 }
+      scheduler_role                   = { suffix = "-scheduler-role", max_length = 64 }
+      scheduler_policy                 = { suffix = "-scheduler-policy", max_length = 128 }
+    },
+    { for port in var.additional_ports : "target_group_${port.name}" => { suffix = "-${port.name}-tg", max_length = 32, hyphens_only = true } },
+    { for task in var.scheduled_tasks : "schedule_${task.name}" => { suffix = "-${task.name}", max_length = 64 } }
+  )
+}
+
+locals {
+  name_base = var.name_prefix != null ? var.name_prefix : var.service_name
+
+  # Servicio creado, sea cual sea el modo de desired_count
+  ecs_service = one(concat(aws_ecs_service.webapp, aws_ecs_service.webapp_autoscaled))
+
+  # Referenciar el recurso hace que la task definition espere a que el log group exista
+  log_group_name = var.create_log_group ? aws_cloudwatch_log_group.webapp[0].name : var.cloudwatch_log_group_name
+
+  # ECR repository URL format: <account>.dkr.ecr.<region>.amazonaws.com/<repository>
+  ecr_repository = try(regex("^([0-9]{12})\\.dkr\\.ecr\\.[a-z0-9-]+\\.amazonaws\\.com(?:\\.cn)?/(.+)$", var.docker_image), null)
+
+  # Con resolve_image_digest el tag se fija a su digest, así un cambio de imagen aparece en el plan
+  container_image = var.resolve_image_digest ? "${var.docker_image}@${data.aws_ecr_image.webapp[0].image_digest}" : "${var.docker_image}:${var.image_tag}"
+
+  default_listener_arn = var.alb_listener_arn != null ? var.alb_listener_arn : var.alb_load_balancer_arn
+
+  # Protocolos del puerto principal: UDP/TCP_UDP en el NLB requieren port mappings udp
+  nlb_protocol        = try(var.nlb.protocol, null)
+  main_port_protocols = local.nlb_protocol == "UDP" ? ["udp"] : local.nlb_protocol == "TCP_UDP" ? ["tcp", "udp"] : ["tcp"]
+
+  # Reglas de ingreso para el NLB: desde su security group y/o desde los CIDRs de los clientes
+  nlb_ingress_rules = {
+    for rule in (var.nlb == null ? [] : concat(
+      var.nlb.security_group_id != null ? [
+        for protocol in local.main_port_protocols : {
+          key                      = "nlb-sg-${protocol}"
+          protocol                 = protocol
+          source_security_group_id = var.nlb.security_group_id
+          cidr_blocks              = null
+        }
+      ] : [],
+      length(var.nlb.client_cidr_blocks) > 0 ? [
+        for protocol in local.main_port_protocols : {
+          key                      = "clients-${protocol}"
+          protocol                 = protocol
+          source_security_group_id = null
+          cidr_blocks              = var.nlb.client_cidr_blocks
+        }
+      ] : []
+    )) : rule.key => rule
+  }
+
+  # Target groups por puerto: "main" para container_port y uno por cada additional_ports con target_group
+  target_groups = { for name, tg in merge(
+    {
+      main = {
+        name             = module.names.names["target_group_main"]
+        port             = var.container_port
+        protocol         = var.target_group_protocol
+        protocol_version = var.target_group_protocol_version
+        health_check     = var.health_check
+      }
+    },
+    {
+      for port in var.additional_ports : port.name => {
+        name             = module.names.names["target_group_${port.name}"]
+        port             = port.port
+        protocol         = port.target_group.protocol
+        protocol_version = port.target_group.protocol_version
+        health_check     = port.target_group.health_check != null ? port.target_group.health_check : var.health_check
+      } if port.target_group != null
+    }
+  ) : name => tg if var.alb_load_balancer_arn != null }
+
+  # Las reglas del puerto principal mantienen la key "rule-<priority>" para no recrearlas
+  listener_rules = { for key, rule in merge(
+    {
+      for rule in var.listener_rules : "rule-${rule.priority}" => {
+        target_group  = "main"
+        listener_arn  = local.default_listener_arn
+        priority      = rule.priority
+        path_patterns = rule.path_patterns
+        host_headers  = rule.host_headers
+      }
+    },
+    merge([
+      for port in var.additional_ports : {
+        for rule in port.target_group.listener_rules : "${port.name}-rule-${rule.priority}" => {
+          target_group  = port.name
+          listener_arn  = port.target_group.listener_arn != null ? port.target_group.listener_arn : local.default_listener_arn
+          priority      = rule.priority
+          path_patterns = rule.path_patterns
+          host_headers  = rule.host_headers
+        }
+      } if port.target_group != null
+    ]...)
+  ) : key => rule if var.alb_load_balancer_arn != null }
+}
+
+# Resolve image_tag to its digest at plan time (only when resolve_image_digest = true)
+data "aws_ecr_image" "webapp" {
+  count = var.resolve_image_digest ? 1 : 0
+
+  registry_id     = try(local.ecr_repository[0], null)
+  repository_name = try(local.ecr_repository[1], null)
+  image_tag       = var.image_tag
+
+  lifecycle {
+    precondition {
+      condition     = local.ecr_repository != null
+      error_message = "resolve_image_digest requires docker_image to be an ECR repository URL (<account>.dkr.ecr.<region>.amazonaws.com/<repository>)"
+    }
+  }
+}
+
+# Log group gestionado por el módulo (opcional); si no, se usa el existente cloudwatch_log_group_name
+resource "aws_cloudwatch_log_group" "webapp" {
+  count = var.create_log_group ? 1 : 0
+
+  name              = var.cloudwatch_log_group_name
+  retention_in_days = var.log_group_retention_in_days
+  kms_key_id        = var.log_group_kms_key_id
+  log_group_class   = var.log_group_class
+
+  tags = var.common_tags
+}
+
 resource "aws_ecs_task_definition" "webapp" {
   family                   = var.service_name
   requires_compatibilities = ["FARGATE"]
@@ -10,21 +161,38 @@ resource "aws_ecs_task_definition" "webapp" {
   container_definitions = jsonencode([
     {
       name      = var.service_name,
-      image     = "${var.docker_image}:${var.image_tag}",
+      image     = local.container_image,
+      repositoryCredentials = var.repository_credentials_secret_arn != null ? {
+        credentialsParameter = var.repository_credentials_secret_arn
+      } : null,
       essential = true,
       command   = var.container_command,
-      portMappings = [
-        {
-          containerPort = var.container_port,
-          protocol      = "tcp"
-        }
-      ],
+      portMappings = concat(
+        [
+          for protocol in local.main_port_protocols : {
+            containerPort = var.container_port,
+            protocol      = protocol
+          }
+        ],
+        [
+          for port in var.additional_ports : {
+            containerPort = port.port,
+            protocol      = port.protocol
+          }
+        ]
+      ),
       environment = var.environment_variables,
+      environmentFiles = length(var.environment_files) > 0 ? [
+        for file in var.environment_files : {
+          value = file,
+          type  = "s3"
+        }
+      ] : null,
       secrets = var.secret_variables,
       logConfiguration = {
         logDriver = "awslogs",
         options = {
-          "awslogs-group"         = var.cloudwatch_log_group_name,
+          "awslogs-group"         = local.log_group_name,
           "awslogs-region"        = data.aws_region.current.name,
           "awslogs-stream-prefix" = var.service_name
         }
@@ -49,10 +217,96 @@ resource "aws_service_discovery_service" "webapp" {
 
     routing_policy = "MULTIVALUE"
   }
+
+  tags = var.common_tags
 }
 
 
+# Servicio con desired_count gestionado por Terraform (cada apply lo devuelve a min_capacity)
 resource "aws_ecs_service" "webapp" {
+  count = var.manage_desired_count_via_autoscaling ? 0 : 1
+
+  name            = var.service_name
+  cluster         = var.cluster_name
+  task_definition = aws_ecs_task_definition.webapp.arn
+  desired_count   = var.autoscaling_config.min_capacity
+  launch_type     = "FARGATE"
+
+  network_configuration {
+    subnets          = var.subnet_ids
+    security_groups  = concat([aws_security_group.ecs_service.id], var.additional_security_group_ids)
+    assign_public_ip = var.assign_public_ip
+  }
+
+  # Fargate no admite placement strategies ni constraints: el reparto entre AZs lo deciden las subnets y el rebalanceo
+  availability_zone_rebalancing = var.availability_zone_rebalancing == null ? null : (var.availability_zone_rebalancing ? "ENABLED" : "DISABLED")
+
+  dynamic "service_registries" {
+    for_each = var.service_discovery != null ? [1] : []
+    content {
+      registry_arn = aws_service_discovery_service.webapp[0].arn
+    }
+  }
+
+  dynamic "load_balancer" {
+    for_each = local.target_groups
+    content {
+      target_group_arn = aws_lb_target_group.webapp[load_balancer.key].arn
+      container_name   = var.service_name
+      container_port   = load_balancer.value.port
+    }
+  }
+
+  dynamic "load_balancer" {
+    for_each = var.nlb != null ? [1] : []
+    content {
+      target_group_arn = aws_lb_target_group.nlb[0].arn
+      container_name   = var.service_name
+      container_port   = var.container_port
+    }
+  }
+
+  deployment_controller {
+    type = "ECS"
+  }
+
+  deployment_maximum_percent         = var.deployment_config.maximum_percent
+  deployment_minimum_healthy_percent = var.deployment_config.minimum_healthy_percent
+  force_new_deployment               = var.force_new_deployment
+
+  deployment_circuit_breaker {
+    enable   = var.enable_deployment_circuit_breaker
+    rollback = var.enable_deployment_circuit_breaker
+  }
+
+  dynamic "alarms" {
+    for_each = var.deployment_alarms != null ? [var.deployment_alarms] : []
+    content {
+      enable      = true
+      rollback    = alarms.value.rollback
+      alarm_names = local.deployment_alarm_names
+    }
+  }
+
+  # When ALB is configured, depend on listener rules being created first
+  # When for_each is empty (no ALB), this dependency is a no-op
+  # The same applies to the NLB listener: the target group must be attached before the service registers tasks
+  # The migration task (if any) must succeed before the service rolls out the new task definition
+  depends_on = [aws_lb_listener_rule.webapp, aws_lb_listener.nlb, terraform_data.migration]
+
+  tags = var.common_tags
+}
+
+moved {
+  from = aws_ecs_service.webapp
+  to   = aws_ecs_service.webapp[0]
+}
+
+# Misma definición, pero ignorando el desired_count que fija el autoscaling
+# ignore_changes no admite expresiones, de ahí el segundo recurso
+resource "aws_ecs_service" "webapp_autoscaled" {
+  count = var.manage_desired_count_via_autoscaling ? 1 : 0
+
   name            = var.service_name
   cluster         = var.cluster_name
   task_definition = aws_ecs_task_definition.webapp.arn
@@ -61,10 +315,13 @@ resource "aws_ecs_service" "webapp" {
 
   network_configuration {
     subnets          = var.subnet_ids
-    security_groups  = [aws_security_group.ecs_service.id]
-    assign_public_ip = false
+    security_groups  = concat([aws_security_group.ecs_service.id], var.additional_security_group_ids)
+    assign_public_ip = var.assign_public_ip
   }
 
+  # Fargate no admite placement strategies ni constraints: el reparto entre AZs lo deciden las subnets y el rebalanceo
+  availability_zone_rebalancing = var.availability_zone_rebalancing == null ? null : (var.availability_zone_rebalancing ? "ENABLED" : "DISABLED")
+
   dynamic "service_registries" {
     for_each = var.service_discovery != null ? [1] : []
     content {
@@ -73,9 +330,18 @@ resource "aws_ecs_service" "webapp" {
   }
 
   dynamic "load_balancer" {
-    for_each = var.alb_load_balancer_arn != null ? [1] : []
+    for_each = local.target_groups
+    content {
+      target_group_arn = aws_lb_target_group.webapp[load_balancer.key].arn
+      container_name   = var.service_name
+      container_port   = load_balancer.value.port
+    }
+  }
+
+  dynamic "load_balancer" {
+    for_each = var.nlb != null ? [1] : []
     content {
-      target_group_arn = aws_lb_target_group.webapp[0].arn
+      target_group_arn = aws_lb_target_group.nlb[0].arn
       container_name   = var.service_name
       container_port   = var.container_port
     }
@@ -94,46 +360,86 @@ resource "aws_ecs_service" "webapp" {
     rollback = var.enable_deployment_circuit_breaker
   }
 
+  dynamic "alarms" {
+    for_each = var.deployment_alarms != null ? [var.deployment_alarms] : []
+    content {
+      enable      = true
+      rollback    = alarms.value.rollback
+      alarm_names = local.deployment_alarm_names
+    }
+  }
+
   # When ALB is configured, depend on listener rules being created first
   # When for_each is empty (no ALB), this dependency is a no-op
-  depends_on = [aws_lb_listener_rule.webapp]
+  # The same applies to the NLB listener: the target group must be attached before the service registers tasks
+  # The migration task (if any) must succeed before the service rolls out the new task definition
+  depends_on = [aws_lb_listener_rule.webapp, aws_lb_listener.nlb, terraform_data.migration]
 
   tags = var.common_tags
+
+  # El autoscaling ajusta desired_count; Terraform solo lo fija al crear el servicio
+  lifecycle {
+    ignore_changes = [desired_count]
+  }
 }
 
 resource "aws_lb_target_group" "webapp" {
-  count = var.alb_load_balancer_arn != null ? 1 : 0
+  for_each = local.target_groups
 
-  name                 = "${var.service_name}-tg"
-  port                 = var.container_port
-  protocol             = "HTTP"
+  name                 = each.value.name
+  port                 = each.value.port
+  protocol             = each.value.protocol
+  protocol_version     = each.value.protocol_version
   vpc_id               = var.vpc_id
   target_type          = "ip"
   deregistration_delay = var.target_group_deregistration_delay
 
+  slow_start                        = var.target_group_config.slow_start
+  load_balancing_algorithm_type     = var.target_group_config.load_balancing_algorithm_type
+  load_balancing_anomaly_mitigation = var.target_group_config.load_balancing_anomaly_mitigation
+
+  # El bloque se renderiza siempre: quitarlo al desactivar la stickiness la dejaría habilitada en AWS
+  stickiness {
+    enabled         = var.target_group_config.stickiness != null
+    type            = try(var.target_group_config.stickiness.type, "lb_cookie")
+    cookie_duration = try(var.target_group_config.stickiness.cookie_duration, 86400)
+    cookie_name     = try(var.target_group_config.stickiness.cookie_name, null)
+  }
+
   health_check {
-    path                = var.health_check.path
-    interval            = var.health_check.interval
-    timeout             = var.health_check.timeout
-    healthy_threshold   = var.health_check.healthy_threshold
-    unhealthy_threshold = var.health_check.unhealthy_threshold
-    matcher             = var.health_check.matcher
+    path                = each.value.health_check.path
+    interval            = each.value.health_check.interval
+    timeout             = each.value.health_check.timeout
+    healthy_threshold   = each.value.health_check.healthy_threshold
+    unhealthy_threshold = each.value.health_check.unhealthy_threshold
+    matcher             = each.value.health_check.matcher
+    protocol            = each.value.protocol
   }
 
   tags = var.common_tags
+
+  # Al renombrar (service_name o name_prefix) el target group nuevo se crea y el listener rule y el servicio
+  # pasan a él antes de borrar el anterior
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+
+# El target group pasó de count a for_each por puerto; evita recrearlo en stacks existentes
+moved {
+  from = aws_lb_target_group.webapp[0]
+  to   = aws_lb_target_group.webapp["main"]
 }
 
 resource "aws_lb_listener_rule" "webapp" {
-  for_each = var.alb_load_balancer_arn != null ? {
-    for rule in var.listener_rules : "rule-${rule.priority}" => rule
-  } : {}
+  for_each = local.listener_rules
 
-  listener_arn = var.alb_listener_arn != null ? var.alb_listener_arn : var.alb_load_balancer_arn
+  listener_arn = each.value.listener_arn
   priority     = each.value.priority
 
   action {
     type             = "forward"
-    target_group_arn = aws_lb_target_group.webapp[0].arn
+    target_group_arn = aws_lb_target_group.webapp[each.value.target_group].arn
   }
 
   condition {
@@ -155,8 +461,107 @@ resource "aws_lb_listener_rule" "webapp" {
   tags = var.common_tags
 }
 
+# Registros alias de Route 53 para los host_headers de las listener rules
+locals {
+  create_route53_aliases = var.route53 != null && try(var.route53.create_aliases, false) && var.alb_load_balancer_arn != null
+
+  route53_hosts = local.create_route53_aliases ? distinct([
+    for host in flatten([for rule in values(local.listener_rules) : rule.host_headers != null ? rule.host_headers : []]) : lower(host) if !strcontains(host, "?")
+  ]) : []
+
+  # AAAA solo si el ALB tiene IPv6 (dualstack o dualstack-without-public-ipv4)
+  route53_record_types = local.create_route53_aliases ? (
+    startswith(data.aws_lb.alb[0].ip_address_type, "dualstack") ? ["A", "AAAA"] : ["A"]
+  ) : []
+
+  route53_records = {
+    for pair in setproduct(local.route53_hosts, local.route53_record_types) : "${pair[0]}-${pair[1]}" => {
+      name = pair[0]
+      type = pair[1]
+    }
+  }
+}
+
+data "aws_lb" "alb" {
+  count = local.create_route53_aliases ? 1 : 0
+
+  arn = var.alb_load_balancer_arn
+}
+
+data "aws_route53_zone" "alias" {
+  count = local.create_route53_aliases ? 1 : 0
+
+  zone_id = var.route53.zone_id
+}
+
+resource "aws_route53_record" "alias" {
+  for_each = local.route53_records
+
+  zone_id = var.route53.zone_id
+  name    = each.value.name
+  type    = each.value.type
+
+  alias {
+    name                   = data.aws_lb.alb[0].dns_name
+    zone_id                = data.aws_lb.alb[0].zone_id
+    evaluate_target_health = var.route53.evaluate_target_health
+  }
+
+  lifecycle {
+    precondition {
+      condition     = each.value.name == trimsuffix(data.aws_route53_zone.alias[0].name, ".") || endswith(each.value.name, ".${trimsuffix(data.aws_route53_zone.alias[0].name, ".")}")
+      error_message = "Host header ${each.value.name} does not belong to the Route 53 zone ${data.aws_route53_zone.alias[0].name}"
+    }
+  }
+}
+
+resource "aws_lb_target_group" "nlb" {
+  count = var.nlb != null ? 1 : 0
+
+  name                 = module.names.names["target_group_nlb"]
+  port                 = var.container_port
+  protocol             = var.nlb.protocol
+  vpc_id               = var.vpc_id
+  target_type          = "ip"
+  deregistration_delay = var.target_group_deregistration_delay
+  preserve_client_ip   = var.nlb.preserve_client_ip
+
+  health_check {
+    protocol            = var.nlb.health_check.protocol
+    path                = var.nlb.health_check.path
+    matcher             = var.nlb.health_check.matcher
+    interval            = var.nlb.health_check.interval
+    timeout             = var.nlb.health_check.timeout
+    healthy_threshold   = var.nlb.health_check.healthy_threshold
+    unhealthy_threshold = var.nlb.health_check.unhealthy_threshold
+  }
+
+  tags = var.common_tags
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+
+resource "aws_lb_listener" "nlb" {
+  count = try(var.nlb.listener, null) != null ? 1 : 0
+
+  load_balancer_arn = var.nlb.load_balancer_arn
+  port              = var.nlb.listener.port
+  protocol          = coalesce(var.nlb.listener.protocol, var.nlb.protocol)
+  certificate_arn   = var.nlb.listener.certificate_arn
+  ssl_policy        = var.nlb.listener.ssl_policy
+
+  default_action {
+    type             = "forward"
+    target_group_arn = aws_lb_target_group.nlb[0].arn
+  }
+
+  tags = var.common_tags
+}
+
 resource "aws_iam_role" "execution" {
-  name = "${var.service_name}-execution-role"
+  name = module.names.names["execution_role"]
 
   assume_role_policy = jsonencode({
     Version = "2012-10-17",
@@ -172,6 +577,11 @@ resource "aws_iam_role" "execution" {
   })
 
   tags = var.common_tags
+
+  # Al renombrar (service_name o name_prefix) el rol nuevo se crea antes de borrar el anterior
+  lifecycle {
+    create_before_destroy = true
+  }
 }
 
 # Adjuntar política de ejecución de ECS para permisos para extraer imágenes y enviar logs
@@ -180,12 +590,24 @@ resource "aws_iam_role_policy_attachment" "execution_policy" {
   policy_arn = "arn:aws:iam::aws:policy/service-role/AmazonECSTaskExecutionRolePolicy"
 }
 
+locals {
+  # Secretos de Secrets Manager que el Execution Role debe poder leer:
+  # los de secret_variables más las credenciales del registry privado
+  execution_secrets_manager_arns = concat(
+    [
+      for secret in var.secret_variables : secret.valueFrom
+      if can(regex("^arn:aws:secretsmanager:", secret.valueFrom))
+    ],
+    var.repository_credentials_secret_arn != null ? [var.repository_credentials_secret_arn] : []
+  )
+}
+
 # Política inline para permisos de lectura de secretos (SSM y Secrets Manager)
-# Solo se crea cuando se proporcionan secret_variables
+# Solo se crea cuando se proporcionan secret_variables o repository_credentials_secret_arn
 resource "aws_iam_role_policy" "execution_secrets_policy" {
-  count = length(var.secret_variables) > 0 ? 1 : 0
+  count = length(var.secret_variables) > 0 || var.repository_credentials_secret_arn != null ? 1 : 0
 
-  name = "${var.service_name}-execution-secrets-policy"
+  name = module.names.names["execution_secrets_policy"]
   role = aws_iam_role.execution.id
 
   policy = jsonencode({
@@ -205,27 +627,55 @@ resource "aws_iam_role_policy" "execution_secrets_policy" {
         }
       ] : [],
       # Statement para Secrets Manager
-      length([for s in var.secret_variables : s if can(regex("^arn:aws:secretsmanager:", s.valueFrom))]) > 0 ? [
+      length(local.execution_secrets_manager_arns) > 0 ? [
         {
           Effect = "Allow"
           Action = [
             "secretsmanager:GetSecretValue"
           ]
-          Resource = [
-            for secret in var.secret_variables : secret.valueFrom
-            if can(regex("^arn:aws:secretsmanager:", secret.valueFrom))
-          ]
+          Resource = local.execution_secrets_manager_arns
         }
       ] : []
     )
   })
 }
 
+# Política inline para leer los archivos de variables de entorno desde S3
+# Solo se crea cuando se proporcionan environment_files
+resource "aws_iam_role_policy" "execution_environment_files_policy" {
+  count = length(var.environment_files) > 0 ? 1 : 0
+
+  name = module.names.names["execution_env_files_policy"]
+  role = aws_iam_role.execution.id
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect = "Allow"
+        Action = [
+          "s3:GetObject"
+        ]
+        Resource = var.environment_files
+      },
+      {
+        Effect = "Allow"
+        Action = [
+          "s3:GetBucketLocation"
+        ]
+        Resource = distinct([
+          for file in var.environment_files : regex("^(arn:aws[a-zA-Z-]*:s3:::[^/]+)/", file)[0]
+        ])
+      }
+    ]
+  })
+}
+
 # Rol de tarea específico que se crea solo si se proporciona una política JSON
 resource "aws_iam_role" "task" {
   count = var.task_policy_json != null ? 1 : 0
 
-  name = "${var.service_name}-role"
+  name = module.names.names["task_role"]
 
   assume_role_policy = jsonencode({
     Version = "2012-10-17",
@@ -241,13 +691,17 @@ resource "aws_iam_role" "task" {
   })
 
   tags = var.common_tags
+
+  lifecycle {
+    create_before_destroy = true
+  }
 }
 
 # Política en línea que se crea solo si se proporciona una política JSON
 resource "aws_iam_role_policy" "task_policy" {
   count = var.task_policy_json != null ? 1 : 0
 
-  name   = "${var.service_name}-policy"
+  name   = module.names.names["task_policy"]
   role   = aws_iam_role.task[0].id
   policy = var.task_policy_json
 }
@@ -264,7 +718,7 @@ resource "aws_appautoscaling_target" "ecs" {
 
 resource "aws_appautoscaling_policy" "cpu" {
   count              = var.autoscaling_config.cpu != null ? 1 : 0
-  name               = "cpu-scaling-policy-${var.service_name}"
+  name               = module.names.names["cpu_scaling_policy"]
   policy_type        = "TargetTrackingScaling"
   resource_id        = aws_appautoscaling_target.ecs.resource_id
   scalable_dimension = aws_appautoscaling_target.ecs.scalable_dimension
@@ -283,7 +737,7 @@ resource "aws_appautoscaling_policy" "cpu" {
 
 resource "aws_appautoscaling_policy" "memory" {
   count              = var.autoscaling_config.memory != null ? 1 : 0
-  name               = "memory-scaling-policy-${var.service_name}"
+  name               = module.names.names["memory_scaling_policy"]
   policy_type        = "TargetTrackingScaling"
   resource_id        = aws_appautoscaling_target.ecs.resource_id
   scalable_dimension = aws_appautoscaling_target.ecs.scalable_dimension
@@ -301,7 +755,7 @@ resource "aws_appautoscaling_policy" "memory" {
 
 resource "aws_appautoscaling_policy" "alb_request_count" {
   count              = var.autoscaling_config.alb_request_count != null && var.alb_load_balancer_arn != null ? 1 : 0
-  name               = "alb-request-count-scaling-policy-${var.service_name}"
+  name               = module.names.names["alb_request_count_scaling_policy"]
   policy_type        = "TargetTrackingScaling"
   resource_id        = aws_appautoscaling_target.ecs.resource_id
   scalable_dimension = aws_appautoscaling_target.ecs.scalable_dimension
@@ -313,7 +767,7 @@ resource "aws_appautoscaling_policy" "alb_request_count" {
       # Extract ALB name and suffix from the ARN
       # ALB ARN format: arn:aws:elasticloadbalancing:region:account:loadbalancer/app/alb-name/alb-suffix
       # Target Group ARN format: arn:aws:elasticloadbalancing:region:account:targetgroup/tg-name/tg-suffix
-      resource_label = "${replace(var.alb_load_balancer_arn, "/.*:loadbalancer\\//", "")}/targetgroup/${aws_lb_target_group.webapp[0].name}/${split("/", aws_lb_target_group.webapp[0].arn)[2]}"
+      resource_label = "${replace(var.alb_load_balancer_arn, "/.*:loadbalancer\\//", "")}/targetgroup/${aws_lb_target_group.webapp["main"].name}/${split("/", aws_lb_target_group.webapp["main"].arn)[2]}"
     }
     target_value       = var.autoscaling_config.alb_request_count.target_value
     scale_in_cooldown  = var.autoscaling_config.alb_request_count.scale_in_cooldown
@@ -325,23 +779,54 @@ resource "aws_appautoscaling_policy" "alb_request_count" {
 # Get current AWS region
 data "aws_region" "current" {}
 
+# Cuenta de las credenciales del provider (la migración comprueba que el AWS CLI usa la misma)
+data "aws_caller_identity" "current" {}
+
+# AZs de las subnets del servicio, solo para validar min_availability_zones
+# count (no for_each) para que funcione con subnet_ids que aún no se conocen en el plan
+data "aws_subnet" "service" {
+  count = var.min_availability_zones > 1 ? length(var.subnet_ids) : 0
+
+  id = var.subnet_ids[count.index]
+}
+
 # Security Group for ECS Service
 resource "aws_security_group" "ecs_service" {
-  name        = "${var.service_name}-sg"
-  description = "Security group for ECS service ${var.service_name}"
+  name        = module.names.names["security_group"]
+  description = substr("Security group for ECS service ${local.name_base}", 0, 255)
   vpc_id      = var.vpc_id
 
-  egress {
-    from_port   = 0
-    to_port     = 0
-    protocol    = "-1"
-    cidr_blocks = ["0.0.0.0/0"]
-    description = "Allow all outbound traffic"
+  dynamic "egress" {
+    for_each = var.security_group_egress_rules != null ? var.security_group_egress_rules : [
+      {
+        description     = "Allow all outbound traffic"
+        from_port       = 0
+        to_port         = 0
+        protocol        = "-1"
+        cidr_blocks     = ["0.0.0.0/0"]
+        prefix_list_ids = null
+        security_groups = null
+      }
+    ]
+    content {
+      from_port       = egress.value.from_port
+      to_port         = egress.value.to_port
+      protocol        = egress.value.protocol
+      cidr_blocks     = egress.value.cidr_blocks
+      prefix_list_ids = egress.value.prefix_list_ids
+      security_groups = egress.value.security_groups
+      description     = egress.value.description
+    }
   }
 
   tags = merge(var.common_tags, {
-    Name = "${var.service_name}-sg"
+    Name = module.names.names["security_group"]
   })
+
+  # Los nombres de security group son únicos por VPC: al renombrar se crea el nuevo antes de borrar el anterior
+  lifecycle {
+    create_before_destroy = true
+  }
 }
 
 # Security Group Rules for ECS Service
@@ -358,6 +843,8 @@ resource "aws_security_group_rule" "webapp" {
 }
 
 resource "aws_security_group_rule" "vpc" {
+  count = var.allow_vpc_ingress ? 1 : 0
+
   type                     = "ingress"
   from_port                = var.container_port
   to_port                  = var.container_port
@@ -366,3 +853,61 @@ resource "aws_security_group_rule" "vpc" {
   security_group_id        = aws_security_group.ecs_service.id
   description              = "Allow traffic from VPC to container port ${var.container_port}"
 }
+
+# La regla de la VPC pasó a ser condicional; evita recrearla en stacks existentes
+moved {
+  from = aws_security_group_rule.vpc
+  to   = aws_security_group_rule.vpc[0]
+}
+
+# Reglas para additional_ports: desde el ALB si el puerto tiene target group y desde la VPC si allow_vpc_ingress
+resource "aws_security_group_rule" "additional_ports_alb" {
+  for_each = { for name, tg in local.target_groups : name => tg if name != "main" }
+
+  type                     = "ingress"
+  from_port                = each.value.port
+  to_port                  = each.value.port
+  protocol                 = "tcp"
+  source_security_group_id = var.alb_security_group_id
+  security_group_id        = aws_security_group.ecs_service.id
+  description              = "Allow traffic from ALB security group (${var.alb_security_group_id}) to container port ${each.value.port} (${each.key})"
+}
+
+resource "aws_security_group_rule" "additional_ports_vpc" {
+  for_each = var.allow_vpc_ingress ? { for port in var.additional_ports : port.name => port } : {}
+
+  type              = "ingress"
+  from_port         = each.value.port
+  to_port           = each.value.port
+  protocol          = each.value.protocol
+  cidr_blocks       = [var.vpc_cidr_block]
+  security_group_id = aws_security_group.ecs_service.id
+  description       = "Allow traffic from VPC to container port ${each.value.port} (${each.key})"
+}
+
+resource "aws_security_group_rule" "nlb" {
+  for_each = local.nlb_ingress_rules
+
+  type                     = "ingress"
+  from_port                = var.container_port
+  to_port                  = var.container_port
+  protocol                 = each.value.protocol
+  source_security_group_id = each.value.source_security_group_id
+  cidr_blocks              = each.value.cidr_blocks
+  security_group_id        = aws_security_group.ecs_service.id
+  description              = each.value.source_security_group_id != null ? "Allow ${each.value.protocol} traffic from NLB security group (${each.value.source_security_group_id}) to container port ${var.container_port}" : "Allow ${each.value.protocol} traffic from NLB clients to container port ${var.container_port}"
+}
+
+resource "aws_security_group_rule" "additional_ingress" {
+  for_each = { for rule in var.security_group_ingress_rules : rule.description => rule }
+
+  type                     = "ingress"
+  from_port                = coalesce(each.value.from_port, var.container_port)
+  to_port                  = coalesce(each.value.to_port, each.value.from_port, var.container_port)
+  protocol                 = each.value.protocol
+  source_security_group_id = each.value.source_security_group_id
+  prefix_list_ids          = each.value.prefix_list_ids
+  cidr_blocks              = each.value.cidr_blocks
+  security_group_id        = aws_security_group.ecs_service.id
+  description              = each.value.description
+}
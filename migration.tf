@@ -0,0 +1,57 @@
+# Migración previa al despliegue
+# Ejecuta la task definition del servicio una vez con el command de migración antes de que el servicio despliegue la revisión.
+# Si la tarea no termina con exit code 0 el provisioner falla, terraform_data queda tainted y el apply se detiene
+# sin actualizar el servicio; el siguiente apply vuelve a intentar la migración.
+# Requiere AWS CLI y credenciales en la máquina que ejecuta Terraform: el CLI no usa el assume_role, profile ni
+# endpoints del provider, así que el script comprueba que sus credenciales son de la cuenta del provider antes de lanzar la tarea.
+
+locals {
+  migration_started_by = "terraform-migration"
+}
+
+resource "terraform_data" "migration" {
+  count = var.migration != null ? 1 : 0
+
+  # Una ejecución por cada revisión nueva de la task definition o cambio del command
+  triggers_replace = [aws_ecs_task_definition.webapp.arn, var.migration.command]
+
+  # La task definition solo referencia los roles: sin sus políticas la tarea no puede descargar la imagen,
+  # escribir logs ni leer los secretos en el primer apply
+  depends_on = [
+    aws_iam_role_policy_attachment.execution_policy,
+    aws_iam_role_policy.execution_secrets_policy,
+    aws_iam_role_policy.execution_environment_files_policy,
+    aws_iam_role_policy.task_policy,
+    aws_cloudwatch_log_group.webapp,
+  ]
+
+  provisioner "local-exec" {
+    command     = "${path.module}/scripts/run-migration.sh"
+    interpreter = ["bash"]
+
+    environment = {
+      AWS_REGION      = data.aws_region.current.name
+      AWS_ACCOUNT_ID  = data.aws_caller_identity.current.account_id
+      CLUSTER         = var.cluster_name
+      TASK_DEFINITION = aws_ecs_task_definition.webapp.arn
+      CONTAINER_NAME  = var.service_name
+      STARTED_BY      = local.migration_started_by
+      TIMEOUT_SECONDS = tostring(var.migration.timeout_seconds)
+      NETWORK_CONFIGURATION = jsonencode({
+        awsvpcConfiguration = {
+          subnets        = var.subnet_ids
+          securityGroups = concat([aws_security_group.ecs_service.id], var.additional_security_group_ids)
+          assignPublicIp = var.assign_public_ip ? "ENABLED" : "DISABLED"
+        }
+      })
+      OVERRIDES = jsonencode({
+        containerOverrides = [
+          {
+            name    = var.service_name
+            command = var.migration.command
+          }
+        ]
+      })
+    }
+  }
+}
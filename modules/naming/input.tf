@@ -0,0 +1,23 @@
+variable "name" {
+  description = "Base name shared by all resources (service_name or name_prefix)"
+  type        = string
+}
+
+variable "resources" {
+  description = <<-EOT
+    Names to build, by key. Each name is prefix + name + suffix when it fits in max_length.
+    Otherwise the base name is truncated and an 8-character hash of the full name is appended before the suffix.
+    hyphens_only replaces underscores (for target groups, which only accept alphanumerics and hyphens).
+  EOT
+  type = map(object({
+    prefix       = optional(string, "")
+    suffix       = optional(string, "")
+    max_length   = number
+    hyphens_only = optional(bool, false)
+  }))
+
+  validation {
+    condition     = alltrue([for resource in values(var.resources) : length(resource.prefix) + length(resource.suffix) + 10 <= resource.max_length])
+    error_message = "prefix and suffix must leave room for at least 1 character of the base name plus the 9-character hash (-xxxxxxxx) within max_length"
+  }
+}
@@ -0,0 +1,23 @@
+# Nombres deterministas dentro de los límites de AWS
+# Si el nombre completo entra en max_length se usa tal cual (los stacks existentes no cambian de nombre);
+# si no, se trunca el nombre base y se agrega un hash del nombre completo para mantenerlo único.
+# Los separadores (- y _) con los que termine el nombre truncado se quitan para no generar "foo--a1b2c3d4"
+locals {
+  full_names = {
+    for key, resource in var.resources : key => "${resource.prefix}${resource.hyphens_only ? replace(var.name, "_", "-") : var.name}${resource.suffix}"
+  }
+
+  names = {
+    for key, resource in var.resources : key => (
+      length(local.full_names[key]) <= resource.max_length
+      ? local.full_names[key]
+      : join("", [
+        resource.prefix,
+        replace(substr(resource.hyphens_only ? replace(var.name, "_", "-") : var.name, 0, resource.max_length - length(resource.prefix) - length(resource.suffix) - 9), "/[-_]+$/", ""),
+        "-",
+        substr(sha1(local.full_names[key]), 0, 8),
+        resource.suffix,
+      ])
+    )
+  }
+}
@@ -0,0 +1,4 @@
+output "names" {
+  description = "Resource names by key, each within its max_length"
+  value       = local.names
+}
@@ -1,11 +1,61 @@
 output "alb_target_group_arn" {
   description = "ARN of the Target Group connected to the ALB. Null if ALB is not configured."
-  value       = var.alb_load_balancer_arn != null ? aws_lb_target_group.webapp[0].arn : null
+  value       = var.alb_load_balancer_arn != null ? aws_lb_target_group.webapp["main"].arn : null
+}
+
+output "alb_target_group_arns" {
+  description = "Map of Target Group ARNs by port name ('main' for container_port plus each additional_ports entry with a target_group). Empty if ALB is not configured."
+  value       = { for name, tg in aws_lb_target_group.webapp : name => tg.arn }
+}
+
+output "nlb_target_group_arn" {
+  description = "ARN of the Target Group connected to the NLB. Null if NLB is not configured."
+  value       = var.nlb != null ? aws_lb_target_group.nlb[0].arn : null
+}
+
+output "nlb_listener_arn" {
+  description = "ARN of the NLB listener created by the module. Null if nlb.listener is not configured."
+  value       = try(aws_lb_listener.nlb[0].arn, null)
+}
+
+output "route53_record_names" {
+  description = "Host names with Route 53 alias records pointing at the ALB. Empty if route53 is not configured."
+  value       = local.route53_hosts
+}
+
+output "alarm_arns" {
+  description = "ARNs of the CloudWatch alarms created by the module, by alarm (cpu_high, memory_high, ...). Empty if alarms is not configured."
+  value       = { for name, alarm in local.module_alarms : name => alarm[0].arn if length(alarm) > 0 }
+}
+
+output "dashboard_name" {
+  description = "Name of the CloudWatch dashboard. Null if alarms.dashboard is not enabled."
+  value       = try(aws_cloudwatch_dashboard.webapp[0].dashboard_name, null)
+}
+
+output "cloudwatch_log_group_name" {
+  description = "Name of the CloudWatch log group used by the container (created by the module or existing)"
+  value       = local.log_group_name
+}
+
+output "cloudwatch_log_group_arn" {
+  description = "ARN of the CloudWatch log group created by the module. Null if create_log_group is false."
+  value       = try(aws_cloudwatch_log_group.webapp[0].arn, null)
+}
+
+output "scheduled_task_arns" {
+  description = "ARNs of the EventBridge Scheduler schedules, by scheduled_tasks name"
+  value       = { for name, schedule in aws_scheduler_schedule.webapp : name => schedule.arn }
+}
+
+output "scheduler_role_arn" {
+  description = "ARN of the IAM role used by EventBridge Scheduler to run the scheduled tasks. Null if scheduled_tasks is empty."
+  value       = try(aws_iam_role.scheduler[0].arn, null)
 }
 
 output "ecs_service_name" {
   description = "Name of the ECS service"
-  value       = aws_ecs_service.webapp.name
+  value       = local.ecs_service.name
 } 
 
 output "ecs_task_definition_arn" {
@@ -13,6 +63,11 @@ output "ecs_task_definition_arn" {
   value       = aws_ecs_task_definition.webapp.arn
 } 
 
+output "container_image" {
+  description = "Image rendered in the container definition (docker_image:image_tag, or docker_image@digest when resolve_image_digest is enabled)"
+  value       = local.container_image
+}
+
 output "iam_execution_role_arn" {
   description = "ARN of the ECS execution role"
   value       = aws_iam_role.execution.arn
@@ -0,0 +1,127 @@
+# Tareas programadas con EventBridge Scheduler
+# Reutilizan la task definition del servicio (misma imagen y configuración) con un command opcional
+
+locals {
+  scheduled_tasks = { for task in var.scheduled_tasks : task.name => task }
+}
+
+data "aws_ecs_cluster" "scheduled_tasks" {
+  count = length(var.scheduled_tasks) > 0 ? 1 : 0
+
+  cluster_name = var.cluster_name
+}
+
+resource "aws_iam_role" "scheduler" {
+  count = length(var.scheduled_tasks) > 0 ? 1 : 0
+
+  name = module.names.names["scheduler_role"]
+
+  assume_role_policy = jsonencode({
+    Version = "2012-10-17",
+    Statement = [
+      {
+        Action = "sts:AssumeRole",
+        Effect = "Allow",
+        Principal = {
+          Service = "scheduler.amazonaws.com"
+        }
+      }
+    ]
+  })
+
+  tags = var.common_tags
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+
+# RunTask sobre cualquier revisión de la familia, solo en el cluster del servicio
+resource "aws_iam_role_policy" "scheduler" {
+  count = length(var.scheduled_tasks) > 0 ? 1 : 0
+
+  name = module.names.names["scheduler_policy"]
+  role = aws_iam_role.scheduler[0].id
+
+  policy = jsonencode({
+    Version = "2012-10-17",
+    Statement = [
+      {
+        Effect   = "Allow",
+        Action   = "ecs:RunTask",
+        Resource = "${aws_ecs_task_definition.webapp.arn_without_revision}:*",
+        Condition = {
+          ArnEquals = {
+            "ecs:cluster" = data.aws_ecs_cluster.scheduled_tasks[0].arn
+          }
+        }
+      },
+      {
+        Effect   = "Allow",
+        Action   = "ecs:TagResource",
+        Resource = "*",
+        Condition = {
+          StringEquals = {
+            "ecs:CreateAction" = "RunTask"
+          }
+        }
+      },
+      {
+        Effect   = "Allow",
+        Action   = "iam:PassRole",
+        Resource = compact([aws_iam_role.execution.arn, var.task_policy_json != null ? aws_iam_role.task[0].arn : null]),
+        Condition = {
+          StringLike = {
+            "iam:PassedToService" = "ecs-tasks.amazonaws.com"
+          }
+        }
+      }
+    ]
+  })
+}
+
+resource "aws_scheduler_schedule" "webapp" {
+  for_each = local.scheduled_tasks
+
+  name                         = module.names.names["schedule_${each.key}"]
+  schedule_expression          = each.value.schedule_expression
+  schedule_expression_timezone = each.value.schedule_expression_timezone
+  state                        = each.value.enabled ? "ENABLED" : "DISABLED"
+
+  flexible_time_window {
+    mode = "OFF"
+  }
+
+  target {
+    arn      = data.aws_ecs_cluster.scheduled_tasks[0].arn
+    role_arn = aws_iam_role.scheduler[0].arn
+
+    ecs_parameters {
+      task_definition_arn = aws_ecs_task_definition.webapp.arn
+      task_count          = each.value.task_count
+      launch_type         = "FARGATE"
+
+      network_configuration {
+        subnets          = var.subnet_ids
+        security_groups  = concat([aws_security_group.ecs_service.id], var.additional_security_group_ids)
+        assign_public_ip = var.assign_public_ip
+      }
+
+      tags = merge(var.common_tags, {
+        ScheduledTask = each.key
+      })
+    }
+
+    input = each.value.command != null ? jsonencode({
+      containerOverrides = [
+        {
+          name    = var.service_name
+          command = each.value.command
+        }
+      ]
+    }) : null
+  }
+
+  # La policy debe existir antes de que el scheduler intente lanzar la primera tarea
+  depends_on = [aws_iam_role_policy.scheduler]
+}
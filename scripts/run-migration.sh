@@ -0,0 +1,66 @@
+#!/usr/bin/env bash
+# Runs the service task definition once with the migration command override and waits for it to stop
+# Called by terraform_data.migration: any exit code other than 0 fails the apply before the service is updated
+#
+# Environment: AWS_REGION, AWS_ACCOUNT_ID, CLUSTER, TASK_DEFINITION, CONTAINER_NAME, STARTED_BY,
+#              NETWORK_CONFIGURATION, OVERRIDES (JSON for aws ecs run-task), TIMEOUT_SECONDS
+set -euo pipefail
+
+# The AWS CLI uses the local credentials, not the provider's assume_role/profile: refuse to run in another account
+caller_account=$(aws sts get-caller-identity --region "${AWS_REGION}" --query Account --output text)
+if [[ "${caller_account}" != "${AWS_ACCOUNT_ID}" ]]; then
+  echo "AWS CLI credentials belong to account ${caller_account}, but the provider deploys to ${AWS_ACCOUNT_ID}." >&2
+  echo "Export credentials (AWS_PROFILE or AWS_* variables) for account ${AWS_ACCOUNT_ID} before applying." >&2
+  exit 1
+fi
+
+echo "Running migration task ${TASK_DEFINITION} on cluster ${CLUSTER}"
+read -r task_arn failure_reason < <(aws ecs run-task \
+  --region "${AWS_REGION}" \
+  --cluster "${CLUSTER}" \
+  --task-definition "${TASK_DEFINITION}" \
+  --launch-type FARGATE \
+  --started-by "${STARTED_BY}" \
+  --network-configuration "${NETWORK_CONFIGURATION}" \
+  --overrides "${OVERRIDES}" \
+  --query '[tasks[0].taskArn, failures[0].reason]' \
+  --output text)
+
+if [[ -z "${task_arn}" || "${task_arn}" == "None" ]]; then
+  echo "Migration task could not be started: ${failure_reason}" >&2
+  exit 1
+fi
+echo "Migration task started: ${task_arn}"
+
+deadline=$((SECONDS + TIMEOUT_SECONDS))
+while true; do
+  status=$(aws ecs describe-tasks \
+    --region "${AWS_REGION}" \
+    --cluster "${CLUSTER}" \
+    --tasks "${task_arn}" \
+    --query 'tasks[0].lastStatus' \
+    --output text)
+  if [[ "${status}" == "STOPPED" ]]; then
+    break
+  fi
+  if ((SECONDS >= deadline)); then
+    echo "Migration task ${task_arn} did not finish within ${TIMEOUT_SECONDS}s, stopping it" >&2
+    aws ecs stop-task --region "${AWS_REGION}" --cluster "${CLUSTER}" --task "${task_arn}" --reason "Migration timed out" >/dev/null
+    exit 1
+  fi
+  echo "Migration task status: ${status}"
+  sleep 10
+done
+
+read -r exit_code stopped_reason < <(aws ecs describe-tasks \
+  --region "${AWS_REGION}" \
+  --cluster "${CLUSTER}" \
+  --tasks "${task_arn}" \
+  --query "[tasks[0].containers[?name=='${CONTAINER_NAME}'] | [0].exitCode, tasks[0].stoppedReason]" \
+  --output text)
+
+if [[ "${exit_code}" != "0" ]]; then
+  echo "Migration task ${task_arn} failed with exit code ${exit_code}: ${stopped_reason}" >&2
+  exit 1
+fi
+echo "Migration task ${task_arn} completed successfully"
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func testAlarms(t *testing.T, moduleOptions *terraform.Options, infraOutputs *InfrastructureOutputs) {
+	alarmARNs := terraform.OutputMap(t, moduleOptions, "alarm_arns")
+	if _, ok := moduleOptions.Vars["alarms"]; !ok {
+		require.Empty(t, alarmARNs, "No alarms should be created when alarms is not configured")
+		t.Logf("⏭️  Skipping alarms test (alarms not configured)")
+		return
+	}
+
+	serviceName := terraform.Output(t, moduleOptions, "service_name")
+	clusterName := terraform.Output(t, moduleOptions, "cluster_name")
+	serviceDimensions := map[string]string{
+		"ClusterName": clusterName,
+		"ServiceName": serviceName,
+	}
+
+	expectedAlarms := []string{"cpu_high", "memory_high", "running_tasks_low"}
+	var albDimensions map[string]string
+	if infraOutputs.ALBLoadBalancerARN != "" {
+		expectedAlarms = append(expectedAlarms, "target_5xx_rate", "unhealthy_hosts", "target_response_time_p99")
+		targetGroupARN := terraform.Output(t, moduleOptions, "alb_target_group_arn")
+		albDimensions = map[string]string{
+			"LoadBalancer": infraOutputs.ALBLoadBalancerARN[strings.Index(infraOutputs.ALBLoadBalancerARN, "loadbalancer/")+len("loadbalancer/"):],
+			"TargetGroup":  targetGroupARN[strings.Index(targetGroupARN, "targetgroup/"):],
+		}
+	}
+
+	t.Logf("🚨 Verifying alarms...")
+	t.Logf("   Alarm ARNs: %v", alarmARNs)
+	actualAlarms := make([]string, 0, len(alarmARNs))
+	alarmNames := make([]*string, 0, len(alarmARNs))
+	for name, arn := range alarmARNs {
+		actualAlarms = append(actualAlarms, name)
+		alarmNames = append(alarmNames, aws.String(arn[strings.LastIndex(arn, ":")+1:]))
+	}
+	require.ElementsMatch(t, expectedAlarms, actualAlarms)
+
+	cloudWatchClient := newCloudWatchClient(t, infraOutputs.AWSRegion)
+	alarms, err := cloudWatchClient.DescribeAlarms(&cloudwatch.DescribeAlarmsInput{
+		AlarmNames: alarmNames,
+	})
+	require.NoError(t, err)
+	require.Len(t, alarms.MetricAlarms, len(expectedAlarms))
+
+	alarmsByARN := make(map[string]*cloudwatch.MetricAlarm)
+	for _, alarm := range alarms.MetricAlarms {
+		alarmsByARN[aws.StringValue(alarm.AlarmArn)] = alarm
+	}
+
+	for name, arn := range alarmARNs {
+		alarm, ok := alarmsByARN[arn]
+		require.True(t, ok, "Alarm %s (%s) should exist", name, arn)
+		t.Logf("   %s: %s", name, aws.StringValue(alarm.AlarmName))
+
+		// Every alarm notifies the fixture topic
+		require.Contains(t, aws.StringValueSlice(alarm.AlarmActions), infraOutputs.AlarmsTopicARN, "Alarm %s should notify the SNS topic", name)
+
+		// Dimensions must point at this service and its target group, either on the alarm or on its metric queries
+		expectedDimensions := serviceDimensions
+		if name == "target_5xx_rate" || name == "unhealthy_hosts" || name == "target_response_time_p99" {
+			expectedDimensions = albDimensions
+		}
+		if len(alarm.Metrics) == 0 {
+			require.Equal(t, expectedDimensions, dimensionsToMap(alarm.Dimensions), "Alarm %s dimensions", name)
+			continue
+		}
+		for _, query := range alarm.Metrics {
+			if query.MetricStat == nil {
+				continue
+			}
+			require.Equal(t, expectedDimensions, dimensionsToMap(query.MetricStat.Metric.Dimensions), "Alarm %s metric %s dimensions", name, aws.StringValue(query.Id))
+		}
+	}
+
+	// The p99 latency alarm uses an extended statistic
+	if arn, ok := alarmARNs["target_response_time_p99"]; ok {
+		require.Equal(t, "p99", aws.StringValue(alarmsByARN[arn].ExtendedStatistic))
+	}
+
+	// running_tasks_low compares the running tasks with the autoscaling minimum, not with the desired count
+	if arn, ok := alarmARNs["running_tasks_low"]; ok {
+		alarm := alarmsByARN[arn]
+		minCapacity := moduleOptions.Vars["autoscaling_config"].(map[string]interface{})["min_capacity"].(int)
+		require.Equal(t, "RunningTaskCount", aws.StringValue(alarm.MetricName))
+		require.Equal(t, cloudwatch.ComparisonOperatorLessThanThreshold, aws.StringValue(alarm.ComparisonOperator))
+		require.Equal(t, float64(minCapacity), aws.Float64Value(alarm.Threshold))
+	}
+
+	// Dashboard
+	dashboardName := terraform.Output(t, moduleOptions, "dashboard_name")
+	t.Logf("📊 Verifying dashboard %s...", dashboardName)
+	dashboard, err := cloudWatchClient.GetDashboard(&cloudwatch.GetDashboardInput{
+		DashboardName: aws.String(dashboardName),
+	})
+	require.NoError(t, err, "Dashboard should exist when alarms.dashboard is true")
+	require.Contains(t, aws.StringValue(dashboard.DashboardBody), serviceName)
+
+	t.Logf("✅ Alarms tests passed!")
+}
+
+// dimensionsToMap converts CloudWatch dimensions into a name => value map
+func dimensionsToMap(dimensions []*cloudwatch.Dimension) map[string]string {
+	result := make(map[string]string)
+	for _, dimension := range dimensions {
+		result[aws.StringValue(dimension.Name)] = aws.StringValue(dimension.Value)
+	}
+	return result
+}
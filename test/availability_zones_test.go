@@ -0,0 +1,100 @@
+package test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func testAvailabilityZoneSpread(t *testing.T, infraOutputs *InfrastructureOutputs, testName string) {
+	skipIfLocalStack(t, "the emulator runs tasks as local containers, not in the Availability Zones of the subnets")
+
+	scenarioName := fmt.Sprintf("%s-az", testName)
+	region := infraOutputs.AWSRegion
+
+	moduleOptions, err := deployModuleScenario(t, infraOutputs, scenarioName, func(vars map[string]interface{}) {
+		// min = max keeps 2 tasks; the validation requires a policy, its high target never triggers
+		vars["autoscaling_config"] = map[string]interface{}{
+			"min_capacity": 2,
+			"max_capacity": 2,
+			"cpu": map[string]interface{}{
+				"target_value":       95,
+				"scale_in_cooldown":  300,
+				"scale_out_cooldown": 300,
+			},
+		}
+		vars["availability_zone_rebalancing"] = true
+		vars["min_availability_zones"] = 2
+	})
+	require.NoError(t, err, "Scenario with 2 tasks across Availability Zones should apply")
+
+	clusterName := terraform.Output(t, moduleOptions, "cluster_name")
+	serviceName := terraform.Output(t, moduleOptions, "service_name")
+
+	// Availability Zone of each fixture private subnet
+	ec2Client := newEC2Client(t, region)
+	subnets, err := ec2Client.DescribeSubnets(&ec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice(infraOutputs.PrivateSubnetIDs),
+	})
+	require.NoError(t, err)
+	subnetZones := make(map[string]string)
+	for _, subnet := range subnets.Subnets {
+		subnetZones[aws.StringValue(subnet.SubnetId)] = aws.StringValue(subnet.AvailabilityZone)
+	}
+	t.Logf("🗺️  Private subnets: %v", subnetZones)
+
+	t.Logf("⏳ Waiting for 2 running tasks...")
+	_, err = waitForDeploymentCompleted(t, region, clusterName, serviceName)
+	require.NoError(t, err, "Deployment with 2 tasks should complete")
+
+	ecsClient := newECSClient(t, region)
+	taskARNs, err := ecsClient.ListTasks(&ecs.ListTasksInput{
+		Cluster:       aws.String(clusterName),
+		ServiceName:   aws.String(serviceName),
+		DesiredStatus: aws.String(ecs.DesiredStatusRunning),
+	})
+	require.NoError(t, err)
+	require.Len(t, taskARNs.TaskArns, 2)
+
+	tasks, err := ecsClient.DescribeTasks(&ecs.DescribeTasksInput{
+		Cluster: aws.String(clusterName),
+		Tasks:   taskARNs.TaskArns,
+	})
+	require.NoError(t, err)
+
+	zones := make(map[string]bool)
+	for _, task := range tasks.Tasks {
+		zone := aws.StringValue(task.AvailabilityZone)
+		subnetID := taskSubnetID(task)
+		t.Logf("   %s: %s (%s)", aws.StringValue(task.TaskArn), zone, subnetID)
+
+		expectedZone, ok := subnetZones[subnetID]
+		require.True(t, ok, "Task should run in one of the private subnets %v, got %s", infraOutputs.PrivateSubnetIDs, subnetID)
+		require.Equal(t, expectedZone, zone)
+		zones[zone] = true
+	}
+	require.Len(t, zones, 2, "The 2 tasks should run in different Availability Zones")
+
+	rebalancing, err := getServiceAvailabilityZoneRebalancing(t, region, clusterName, serviceName)
+	require.NoError(t, err)
+	require.Equal(t, "ENABLED", rebalancing, "availability_zone_rebalancing = true should enable AZ rebalancing on the service")
+
+	t.Logf("✅ Availability Zone spread tests passed!")
+}
+
+// taskSubnetID returns the subnet of the task ENI (awsvpc network mode)
+func taskSubnetID(task *ecs.Task) string {
+	for _, attachment := range task.Attachments {
+		for _, detail := range attachment.Details {
+			if aws.StringValue(detail.Name) == "subnetId" {
+				return aws.StringValue(detail.Value)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+package test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func testDeploymentAlarmsRollback(t *testing.T, infraOutputs *InfrastructureOutputs, testName string) {
+	skipIfLocalStack(t, "the emulator does not evaluate deployment alarms or roll back deployments")
+
+	if infraOutputs.ALBLoadBalancerARN == "" || infraOutputs.AlarmsTopicARN == "" {
+		t.Logf("⏭️  Skipping deployment alarms test (ALB or alarms topic not configured)")
+		return
+	}
+
+	scenarioName := fmt.Sprintf("%s-dalm", testName)
+	region := infraOutputs.AWSRegion
+
+	moduleOptions, err := deployModuleScenario(t, infraOutputs, scenarioName, func(vars map[string]interface{}) {
+		// A single breaching minute of unhealthy targets fails the deployment
+		vars["alarms"] = map[string]interface{}{
+			"sns_topic_arns": []string{infraOutputs.AlarmsTopicARN},
+			"unhealthy_hosts": map[string]interface{}{
+				"period":             60,
+				"evaluation_periods": 1,
+			},
+		}
+		vars["deployment_alarms"] = map[string]interface{}{
+			"module_alarms": []string{"unhealthy_hosts"},
+		}
+		vars["health_check"] = map[string]interface{}{
+			"path":                "/",
+			"interval":            10,
+			"timeout":             5,
+			"healthy_threshold":   2,
+			"unhealthy_threshold": 2,
+			"matcher":             "200-399",
+		}
+	})
+	require.NoError(t, err, "Scenario with deployment_alarms should apply")
+
+	clusterName := terraform.Output(t, moduleOptions, "cluster_name")
+	serviceName := terraform.Output(t, moduleOptions, "service_name")
+	originalTaskDefinitionARN := terraform.Output(t, moduleOptions, "ecs_task_definition_arn")
+
+	t.Logf("⏳ Waiting for the initial deployment to complete...")
+	_, err = waitForDeploymentCompleted(t, region, clusterName, serviceName)
+	require.NoError(t, err, "Initial deployment should complete")
+	t.Logf("   Original task definition: %s", originalTaskDefinitionARN)
+
+	// Broken release: a different image_tag that starts fine but answers 500 everywhere,
+	// so only the deployment alarm (not the circuit breaker) can catch it
+	t.Logf("💥 Deploying a broken release...")
+	deployedAt := time.Now()
+	moduleOptions.Vars["image_tag"] = "stable-alpine"
+	moduleOptions.Vars["container_command"] = []string{
+		"sh", "-c", "echo 'server { listen 80 default_server; return 500; }' > /etc/nginx/conf.d/default.conf && exec nginx -g 'daemon off;'",
+	}
+	_, err = terraform.ApplyE(t, moduleOptions)
+	require.NoError(t, err, "Broken release should apply (ECS rolls it back afterwards)")
+	brokenTaskDefinitionARN := terraform.Output(t, moduleOptions, "ecs_task_definition_arn")
+	require.NotEqual(t, originalTaskDefinitionARN, brokenTaskDefinitionARN)
+
+	t.Logf("⏪ Waiting for ECS to roll back to %s...", originalTaskDefinitionARN)
+	failure, err := waitForRollback(t, region, clusterName, serviceName, originalTaskDefinitionARN, deployedAt)
+	require.NoError(t, err, "ECS should roll back the broken release")
+	t.Logf("   Failure event: %s", failure)
+	require.Contains(t, failure, "alarm", "The deployment should fail because of the deployment alarm")
+
+	t.Logf("✅ Deployment alarms rollback tests passed!")
+}
+
+func testCircuitBreakerRollback(t *testing.T, infraOutputs *InfrastructureOutputs, testName string) {
+	skipIfLocalStack(t, "the emulator does not track deployment rolloutState or trigger the circuit breaker")
+
+	scenarioName := fmt.Sprintf("%s-cb", testName)
+	region := infraOutputs.AWSRegion
+
+	moduleOptions, err := deployModuleScenario(t, infraOutputs, scenarioName, func(vars map[string]interface{}) {
+		vars["enable_deployment_circuit_breaker"] = true
+	})
+	require.NoError(t, err, "Scenario with the circuit breaker enabled should apply")
+
+	clusterName := terraform.Output(t, moduleOptions, "cluster_name")
+	serviceName := terraform.Output(t, moduleOptions, "service_name")
+	originalTaskDefinitionARN := terraform.Output(t, moduleOptions, "ecs_task_definition_arn")
+
+	t.Logf("⏳ Waiting for the initial deployment to complete...")
+	_, err = waitForDeploymentCompleted(t, region, clusterName, serviceName)
+	require.NoError(t, err, "Initial deployment should complete")
+	t.Logf("   Original task definition: %s", originalTaskDefinitionARN)
+
+	// A tag that doesn't exist: tasks fail to pull the image and never start
+	t.Logf("💥 Deploying a non-existent image tag...")
+	deployedAt := time.Now()
+	moduleOptions.Vars["image_tag"] = fmt.Sprintf("does-not-exist-%s", testName)
+	moduleOptions.Vars["resolve_image_digest"] = false
+	_, err = terraform.ApplyE(t, moduleOptions)
+	require.NoError(t, err, "Release with a non-existent tag should apply (the circuit breaker rolls it back afterwards)")
+	brokenTaskDefinitionARN := terraform.Output(t, moduleOptions, "ecs_task_definition_arn")
+	require.NotEqual(t, originalTaskDefinitionARN, brokenTaskDefinitionARN)
+
+	t.Logf("🔌 Waiting for the circuit breaker to fail the deployment...")
+	failedDeployment, err := waitForRolloutState(t, region, clusterName, serviceName, brokenTaskDefinitionARN, ecs.DeploymentRolloutStateFailed)
+	require.NoError(t, err, "The deployment with the non-existent tag should reach rolloutState FAILED")
+	t.Logf("   Rollout state reason: %s", aws.StringValue(failedDeployment.RolloutStateReason))
+	require.Greater(t, aws.Int64Value(failedDeployment.FailedTasks), int64(0))
+
+	t.Logf("⏪ Waiting for ECS to roll back to %s...", originalTaskDefinitionARN)
+	failure, err := waitForRollback(t, region, clusterName, serviceName, originalTaskDefinitionARN, deployedAt)
+	require.NoError(t, err, "The primary deployment should use the original task definition after the rollback")
+	t.Logf("   Failure event: %s", failure)
+
+	t.Logf("✅ Circuit breaker rollback tests passed!")
+}
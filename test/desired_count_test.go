@@ -0,0 +1,165 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func testDesiredCountManagedByAutoscaling(t *testing.T, infraOutputs *InfrastructureOutputs, testName string) {
+	scenarioName := fmt.Sprintf("%s-dc", testName)
+	region := infraOutputs.AWSRegion
+
+	moduleOptions, err := deployModuleScenario(t, infraOutputs, scenarioName, func(vars map[string]interface{}) {
+		vars["manage_desired_count_via_autoscaling"] = true
+		// The validation requires a policy: a high CPU target and a long scale-in cooldown keep the
+		// autoscaler from scaling the service back in while the test runs
+		vars["autoscaling_config"] = map[string]interface{}{
+			"min_capacity": 1,
+			"max_capacity": 3,
+			"cpu": map[string]interface{}{
+				"target_value":       95,
+				"scale_in_cooldown":  3600,
+				"scale_out_cooldown": 300,
+			},
+		}
+	})
+	require.NoError(t, err, "Scenario with manage_desired_count_via_autoscaling should apply")
+
+	clusterName := terraform.Output(t, moduleOptions, "cluster_name")
+	serviceName := terraform.Output(t, moduleOptions, "ecs_service_name")
+
+	_, err = waitForDeploymentCompleted(t, region, clusterName, serviceName)
+	require.NoError(t, err, "Initial deployment should complete")
+
+	// Simulate a scale-out by the autoscaler
+	t.Logf("📈 Scaling %s to 2 tasks outside Terraform...", serviceName)
+	ecsClient := newECSClient(t, region)
+	_, err = ecsClient.UpdateService(&ecs.UpdateServiceInput{
+		Cluster:      aws.String(clusterName),
+		Service:      aws.String(serviceName),
+		DesiredCount: aws.Int64(2),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), aws.Int64Value(getECSService(t, region, clusterName, serviceName).DesiredCount))
+
+	t.Logf("📝 Planning after the scale-out...")
+	plan, err := planModule(t, moduleOptions)
+	require.NoError(t, err, "Plan should succeed")
+
+	serviceChange, ok := plan.ResourceChangesMap["aws_ecs_service.webapp_autoscaled[0]"]
+	require.True(t, ok, "The plan should include aws_ecs_service.webapp_autoscaled[0]")
+	t.Logf("   Service actions: %v", serviceChange.Change.Actions)
+	require.True(t, serviceChange.Change.Actions.NoOp(), "The plan should not change the service after a scale-out, got %v", serviceChange.Change.Actions)
+
+	after, ok := serviceChange.Change.After.(map[string]interface{})
+	require.True(t, ok)
+	require.EqualValues(t, 2, after["desired_count"], "The plan should keep the desired_count set by autoscaling")
+
+	t.Logf("✅ Desired count managed by autoscaling tests passed!")
+}
+
+// testAutoscaledServiceMatchesService plans the module in both manage_desired_count_via_autoscaling modes with the
+// same inputs: aws_ecs_service.webapp and aws_ecs_service.webapp_autoscaled are copies that only differ in
+// ignore_changes, so any other difference means a change was made to one copy only
+func testAutoscaledServiceMatchesService(t *testing.T, infraOutputs *InfrastructureOutputs, testName string) {
+	scenarioName := fmt.Sprintf("%s-dcp", testName)
+
+	moduleDir, err := files.CopyTerraformFolderToTemp("..", scenarioName)
+	require.NoError(t, err)
+	moduleOptions := setupScenarioOptions(t, moduleDir, infraOutputs, scenarioName)
+	moduleOptions.Vars["availability_zone_rebalancing"] = true
+	_, err = terraform.InitE(t, moduleOptions)
+	require.NoError(t, err)
+
+	planService := func(autoscaled bool, address string) (interface{}, interface{}) {
+		planOptions, err := moduleOptions.Clone()
+		require.NoError(t, err)
+		planOptions.Vars["manage_desired_count_via_autoscaling"] = autoscaled
+
+		t.Logf("📝 Planning with manage_desired_count_via_autoscaling = %t...", autoscaled)
+		plan, err := planModule(t, planOptions)
+		require.NoError(t, err, "Plan should succeed")
+
+		change, ok := plan.ResourceChangesMap[address]
+		require.True(t, ok, "The plan should include %s", address)
+		return change.Change.After, change.Change.AfterUnknown
+	}
+
+	managedAfter, managedUnknown := planService(false, "aws_ecs_service.webapp[0]")
+	autoscaledAfter, autoscaledUnknown := planService(true, "aws_ecs_service.webapp_autoscaled[0]")
+
+	var differences []string
+	for _, difference := range diffPlanValues("", managedAfter, autoscaledAfter, nil) {
+		if !strings.HasPrefix(difference, "desired_count:") {
+			differences = append(differences, difference)
+		}
+	}
+	require.Empty(t, differences, "aws_ecs_service.webapp and aws_ecs_service.webapp_autoscaled have drifted apart")
+	require.Equal(t, managedUnknown, autoscaledUnknown, "Both service copies should have the same attributes known after apply")
+
+	t.Logf("✅ Both service copies plan the same service")
+}
+
+// TestAutoscaledServiceDefinitionMatchesService fails when the two copies of the service in main.tf drift apart:
+// only count and the lifecycle block (ignore_changes) may differ, whatever the inputs (no AWS credentials needed)
+func TestAutoscaledServiceDefinitionMatchesService(t *testing.T) {
+	source, err := os.ReadFile("../main.tf")
+	require.NoError(t, err)
+
+	service := serviceDefinition(t, string(source), "webapp")
+	autoscaled := serviceDefinition(t, string(source), "webapp_autoscaled")
+	require.Equal(t, service, autoscaled, "aws_ecs_service.webapp and aws_ecs_service.webapp_autoscaled should only differ in count and lifecycle")
+}
+
+// serviceDefinition returns the body of an aws_ecs_service block of main.tf without its count line and its
+// lifecycle block (with the comments right above it)
+func serviceDefinition(t *testing.T, source, name string) []string {
+	lines := strings.Split(source, "\n")
+	start := -1
+	for i, line := range lines {
+		if line == fmt.Sprintf(`resource "aws_ecs_service" %q {`, name) {
+			start = i + 1
+			break
+		}
+	}
+	require.NotEqual(t, -1, start, "main.tf should define aws_ecs_service.%s", name)
+
+	var body []string
+	inLifecycle := false
+	for _, line := range lines[start:] {
+		switch {
+		case line == "}":
+			return trimBlankLines(body)
+		case inLifecycle:
+			inLifecycle = line != "  }"
+		case line == "  lifecycle {":
+			inLifecycle = true
+			for len(body) > 0 && strings.HasPrefix(body[len(body)-1], "  #") {
+				body = body[:len(body)-1]
+			}
+		case strings.HasPrefix(line, "  count "):
+		default:
+			body = append(body, line)
+		}
+	}
+	t.Fatalf("aws_ecs_service.%s is not closed in main.tf", name)
+	return nil
+}
+
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
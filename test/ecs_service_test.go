@@ -1,12 +1,13 @@
 package test
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go/service/ecs"
-	terratestaws "github.com/gruntwork-io/terratest/modules/aws"
 	"github.com/gruntwork-io/terratest/modules/terraform"
 	"github.com/stretchr/testify/require"
 )
@@ -24,7 +25,7 @@ func testECSService(t *testing.T, moduleOptions *terraform.Options, infraOutputs
 	t.Logf("   Region: %s", region)
 
 	// Get ECS service
-	ecsClient := terratestaws.NewEcsClient(t, region)
+	ecsClient := newECSClient(t, region)
 	t.Logf("📡 Calling DescribeServices API...")
 	service, err := ecsClient.DescribeServices(&ecs.DescribeServicesInput{
 		Cluster:  aws.String(clusterName),
@@ -74,14 +75,18 @@ func testECSService(t *testing.T, moduleOptions *terraform.Options, infraOutputs
 	require.NotNil(t, ecsService.NetworkConfiguration)
 	require.NotNil(t, ecsService.NetworkConfiguration.AwsvpcConfiguration)
 
+	expectedAssignPublicIP := "DISABLED"
+	if assignPublicIPVar, _ := moduleOptions.Vars["assign_public_ip"].(bool); assignPublicIPVar {
+		expectedAssignPublicIP = "ENABLED"
+	}
 	assignPublicIP := *ecsService.NetworkConfiguration.AwsvpcConfiguration.AssignPublicIp
-	t.Logf("   Assign Public IP: %s (expected: DISABLED)", assignPublicIP)
-	require.Equal(t, "DISABLED", assignPublicIP)
+	t.Logf("   Assign Public IP: %s (expected: %s)", assignPublicIP, expectedAssignPublicIP)
+	require.Equal(t, expectedAssignPublicIP, assignPublicIP)
 
-	expectedSubnetCount := len(infraOutputs.PrivateSubnetIDs)
-	actualSubnetCount := len(ecsService.NetworkConfiguration.AwsvpcConfiguration.Subnets)
-	t.Logf("   Subnet Count: %d (expected: %d)", actualSubnetCount, expectedSubnetCount)
-	require.Equal(t, expectedSubnetCount, actualSubnetCount)
+	expectedSubnets := moduleOptions.Vars["subnet_ids"].([]string)
+	actualSubnets := aws.StringValueSlice(ecsService.NetworkConfiguration.AwsvpcConfiguration.Subnets)
+	t.Logf("   Subnets: %v (expected: %v)", actualSubnets, expectedSubnets)
+	require.ElementsMatch(t, expectedSubnets, actualSubnets)
 
 	// Verify load balancer configuration (only if ALB is configured)
 	if infraOutputs.ALBLoadBalancerARN != "" {
@@ -125,8 +130,18 @@ func testECSService(t *testing.T, moduleOptions *terraform.Options, infraOutputs
 	t.Logf("   Container Name: %s (expected: %s)", *containerDef.Name, serviceName)
 	require.Equal(t, serviceName, *containerDef.Name)
 
-	t.Logf("   Container Image: %s (expected: nginx:latest)", *containerDef.Image)
-	require.Equal(t, "nginx:latest", *containerDef.Image)
+	// The image is rendered as docker_image:image_tag, or docker_image@sha256:... when resolve_image_digest is enabled
+	dockerImage := moduleOptions.Vars["docker_image"].(string)
+	imageTag := moduleOptions.Vars["image_tag"].(string)
+	containerImage := terraform.Output(t, moduleOptions, "container_image")
+	if resolveDigest, _ := moduleOptions.Vars["resolve_image_digest"].(bool); resolveDigest {
+		t.Logf("   Container Image: %s (expected: %s@sha256:...)", *containerDef.Image, dockerImage)
+		require.True(t, strings.HasPrefix(*containerDef.Image, dockerImage+"@sha256:"), "Image should be pinned to a sha256 digest")
+	} else {
+		t.Logf("   Container Image: %s (expected: %s:%s)", *containerDef.Image, dockerImage, imageTag)
+		require.Equal(t, dockerImage+":"+imageTag, *containerDef.Image)
+	}
+	require.Equal(t, containerImage, *containerDef.Image, "container_image output should match the task definition image")
 
 	t.Logf("   Essential: %v (expected: true)", *containerDef.Essential)
 	require.True(t, *containerDef.Essential)
@@ -208,12 +223,20 @@ func testECSService(t *testing.T, moduleOptions *terraform.Options, infraOutputs
 	}
 	require.Contains(t, containerDef.LogConfiguration.Options, "awslogs-group")
 	logGroupNameValue := containerDef.LogConfiguration.Options["awslogs-group"]
+	logGroupName := terraform.Output(t, moduleOptions, "cloudwatch_log_group_name")
 	if logGroupNameValue != nil {
-		require.Equal(t, infraOutputs.CloudWatchLogGroupName, *logGroupNameValue)
+		require.Equal(t, moduleOptions.Vars["cloudwatch_log_group_name"], *logGroupNameValue)
+		require.Equal(t, logGroupName, *logGroupNameValue)
 	} else {
 		t.Fatal("awslogs-group value is nil")
 	}
 
+	// Verify the container logs actually land in the group
+	t.Logf("📝 Waiting for a log event in %s...", logGroupName)
+	message, err := waitForLogEvent(t, region, logGroupName, serviceName+"/", "")
+	require.NoError(t, err, "Container logs should land in the log group")
+	t.Logf("   First log event: %s", message)
+
 	// Verify deployment configuration
 	t.Logf("🚀 Verifying deployment configuration...")
 	if ecsService.DeploymentConfiguration == nil {
@@ -233,3 +256,61 @@ func testECSService(t *testing.T, moduleOptions *terraform.Options, infraOutputs
 
 	t.Logf("✅ All ECS Service tests passed!")
 }
+
+func testModuleLogGroup(t *testing.T, infraOutputs *InfrastructureOutputs, testName string) {
+	scenarioName := fmt.Sprintf("%s-logs", testName)
+	logGroupName := fmt.Sprintf("/ecs/%s", scenarioName)
+
+	moduleOptions, err := deployModuleScenario(t, infraOutputs, scenarioName, func(vars map[string]interface{}) {
+		vars["create_log_group"] = true
+		vars["cloudwatch_log_group_name"] = logGroupName
+		vars["log_group_retention_in_days"] = 1
+		vars["log_group_class"] = "STANDARD"
+		if infraOutputs.LogsKMSKeyARN != "" {
+			vars["log_group_kms_key_id"] = infraOutputs.LogsKMSKeyARN
+		}
+	})
+	require.NoError(t, err, "Scenario with create_log_group should apply")
+
+	require.Equal(t, logGroupName, terraform.Output(t, moduleOptions, "cloudwatch_log_group_name"))
+	require.NotEmpty(t, terraform.Output(t, moduleOptions, "cloudwatch_log_group_arn"))
+
+	// Verify retention, class and encryption of the module log group
+	t.Logf("🗂️  Verifying log group %s...", logGroupName)
+	logsClient := newCloudWatchLogsClient(t, infraOutputs.AWSRegion)
+	logGroups, err := logsClient.DescribeLogGroups(&cloudwatchlogs.DescribeLogGroupsInput{
+		LogGroupNamePrefix: aws.String(logGroupName),
+	})
+	require.NoError(t, err)
+	require.Len(t, logGroups.LogGroups, 1)
+	logGroup := logGroups.LogGroups[0]
+	t.Logf("   Retention: %d days", aws.Int64Value(logGroup.RetentionInDays))
+	t.Logf("   Class: %s", aws.StringValue(logGroup.LogGroupClass))
+	t.Logf("   KMS Key: %s", aws.StringValue(logGroup.KmsKeyId))
+	require.Equal(t, int64(1), aws.Int64Value(logGroup.RetentionInDays))
+	if !isLocalStack() {
+		require.Equal(t, "STANDARD", aws.StringValue(logGroup.LogGroupClass))
+	}
+	if infraOutputs.LogsKMSKeyARN != "" {
+		require.Equal(t, infraOutputs.LogsKMSKeyARN, aws.StringValue(logGroup.KmsKeyId))
+	}
+
+	// testECSService checks the task definition points at the group and fetches a log event from it
+	waitForECSServiceStable(t, moduleOptions)
+	testECSService(t, moduleOptions, infraOutputs)
+}
+
+func testPublicSubnetMode(t *testing.T, infraOutputs *InfrastructureOutputs, testName string) {
+	scenarioName := fmt.Sprintf("%s-pub", testName)
+
+	// Tasks run in the public subnets and reach Docker Hub through the Internet Gateway, no NAT involved
+	moduleOptions, err := deployModuleScenario(t, infraOutputs, scenarioName, func(vars map[string]interface{}) {
+		vars["subnet_ids"] = infraOutputs.PublicSubnetIDs
+		vars["assign_public_ip"] = true
+	})
+	require.NoError(t, err, "Scenario with assign_public_ip should apply")
+
+	waitForECSServiceStable(t, moduleOptions)
+
+	testECSService(t, moduleOptions, infraOutputs)
+}
@@ -0,0 +1,82 @@
+package test
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func testEnvironmentFiles(t *testing.T, infraOutputs *InfrastructureOutputs, testName string) {
+	scenarioName := fmt.Sprintf("%s-envf", testName)
+	region := infraOutputs.AWSRegion
+	expectedValue := fmt.Sprintf("from-env-file-%s", testName)
+
+	t.Logf("🔍 Testing environment files")
+	t.Logf("   Bucket: %s", infraOutputs.EnvFilesBucketName)
+
+	// Upload the .env file before applying, ECS reads it when the task starts
+	envFileARN := uploadEnvironmentFile(t, region, infraOutputs.EnvFilesBucketName, fmt.Sprintf("%s/app.env", scenarioName), map[string]string{
+		"ENV_FILE_VALUE": expectedValue,
+	})
+	t.Logf("   Env File ARN: %s", envFileARN)
+
+	moduleOptions, err := deployModuleScenario(t, infraOutputs, scenarioName, func(vars map[string]interface{}) {
+		vars["environment_files"] = []string{envFileARN}
+		// Print the variable before starting nginx so it can be read from CloudWatch Logs
+		vars["container_command"] = []string{
+			"sh", "-c", "echo \"ENV_FILE_VALUE=$ENV_FILE_VALUE\" && exec nginx -g 'daemon off;'",
+		}
+	})
+	require.NoError(t, err, "Scenario with environment_files should apply")
+
+	serviceName := terraform.Output(t, moduleOptions, "service_name")
+	taskDefinitionARN := terraform.Output(t, moduleOptions, "ecs_task_definition_arn")
+	executionRoleARN := terraform.Output(t, moduleOptions, "iam_execution_role_arn")
+
+	// Verify the task definition references the env file
+	t.Logf("📦 Verifying environmentFiles in task definition...")
+	ecsClient := newECSClient(t, region)
+	taskDef, err := ecsClient.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String(taskDefinitionARN),
+	})
+	require.NoError(t, err)
+	require.Len(t, taskDef.TaskDefinition.ContainerDefinitions, 1)
+
+	containerDef := taskDef.TaskDefinition.ContainerDefinitions[0]
+	require.Len(t, containerDef.EnvironmentFiles, 1, "Container should have 1 environment file configured")
+	require.Equal(t, envFileARN, aws.StringValue(containerDef.EnvironmentFiles[0].Value))
+	require.Equal(t, "s3", aws.StringValue(containerDef.EnvironmentFiles[0].Type))
+
+	// Verify the execution role can read exactly that object
+	t.Logf("🔐 Verifying environment files policy...")
+	iamClient := newIAMClient(t, region)
+	roleName := executionRoleARN[strings.LastIndex(executionRoleARN, "/")+1:]
+	policyDoc, err := iamClient.GetRolePolicy(&iam.GetRolePolicyInput{
+		RoleName:   aws.String(roleName),
+		PolicyName: aws.String(fmt.Sprintf("%s-execution-env-files-policy", serviceName)),
+	})
+	require.NoError(t, err, "Environment files policy should exist when environment_files are provided")
+
+	policyDocStr, err := url.QueryUnescape(aws.StringValue(policyDoc.PolicyDocument))
+	require.NoError(t, err, "Failed to decode policy document")
+	require.Contains(t, policyDocStr, "s3:GetObject")
+	require.Contains(t, policyDocStr, envFileARN)
+	require.Contains(t, policyDocStr, "s3:GetBucketLocation")
+	require.Contains(t, policyDocStr, fmt.Sprintf("arn:aws:s3:::%s", infraOutputs.EnvFilesBucketName))
+
+	// Verify the running container actually received the value
+	t.Logf("📝 Waiting for the container to log ENV_FILE_VALUE...")
+	message, err := waitForLogEvent(t, region, infraOutputs.CloudWatchLogGroupName, serviceName+"/", fmt.Sprintf("%q", "ENV_FILE_VALUE="))
+	require.NoError(t, err, "Container should log the value loaded from the environment file")
+	t.Logf("   Log message: %s", message)
+	require.Contains(t, message, fmt.Sprintf("ENV_FILE_VALUE=%s", expectedValue))
+
+	t.Logf("✅ Environment files tests passed!")
+}
@@ -1,6 +1,6 @@
 # Security Group for ALB
 resource "aws_security_group" "alb" {
-  name        = "terratest-fixtures-alb-sg"
+  name        = "${var.name_prefix}-alb-sg"
   description = "Security group for test ALB"
   vpc_id      = aws_vpc.main.id
 
@@ -11,6 +11,13 @@ resource "aws_security_group" "alb" {
     cidr_blocks = ["0.0.0.0/0"]
   }
 
+  ingress {
+    from_port   = 443
+    to_port     = 443
+    protocol    = "tcp"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
   egress {
     from_port   = 0
     to_port     = 0
@@ -19,15 +26,15 @@ resource "aws_security_group" "alb" {
   }
 
   tags = {
-    Name      = "terratest-fixtures-alb-sg"
+    Name      = "${var.name_prefix}-alb-sg"
     ManagedBy = "terratest"
-    TestName  = "terratest-fixtures"
+    TestName  = var.name_prefix
   }
 }
 
 # Application Load Balancer
 resource "aws_lb" "main" {
-  name               = "terratest-fixtures-alb"
+  name               = "${var.name_prefix}-alb"
   internal           = false
   load_balancer_type = "application"
   security_groups    = [aws_security_group.alb.id]
@@ -36,15 +43,15 @@ resource "aws_lb" "main" {
   enable_deletion_protection = false
 
   tags = {
-    Name      = "terratest-fixtures-alb"
+    Name      = "${var.name_prefix}-alb"
     ManagedBy = "terratest"
-    TestName  = "terratest-fixtures"
+    TestName  = var.name_prefix
   }
 }
 
 # Default Target Group (required for listener)
 resource "aws_lb_target_group" "default" {
-  name        = "terratest-fixtures-default-tg"
+  name        = "${var.name_prefix}-default-tg"
   port        = 80
   protocol    = "HTTP"
   vpc_id      = aws_vpc.main.id
@@ -61,9 +68,9 @@ resource "aws_lb_target_group" "default" {
   }
 
   tags = {
-    Name      = "terratest-fixtures-default-tg"
+    Name      = "${var.name_prefix}-default-tg"
     ManagedBy = "terratest"
-    TestName  = "terratest-fixtures"
+    TestName  = var.name_prefix
   }
 }
 
@@ -78,3 +85,56 @@ resource "aws_lb_listener" "main" {
     target_group_arn = aws_lb_target_group.default.arn
   }
 }
+
+# Self-signed certificate for the HTTPS listener
+# gRPC target groups can only be attached to HTTPS listeners
+resource "tls_private_key" "alb" {
+  algorithm = "RSA"
+  rsa_bits  = 2048
+}
+
+resource "tls_self_signed_cert" "alb" {
+  private_key_pem       = tls_private_key.alb.private_key_pem
+  validity_period_hours = 24
+
+  subject {
+    common_name  = "${var.name_prefix}.example.com"
+    organization = "Terratest"
+  }
+
+  allowed_uses = [
+    "key_encipherment",
+    "digital_signature",
+    "server_auth",
+  ]
+}
+
+resource "aws_acm_certificate" "alb" {
+  private_key      = tls_private_key.alb.private_key_pem
+  certificate_body = tls_self_signed_cert.alb.cert_pem
+
+  tags = {
+    Name      = "${var.name_prefix}-alb-cert"
+    ManagedBy = "terratest"
+    TestName  = var.name_prefix
+  }
+}
+
+# HTTPS Listener (used by gRPC/HTTP2 scenarios)
+resource "aws_lb_listener" "https" {
+  load_balancer_arn = aws_lb.main.arn
+  port              = "443"
+  protocol          = "HTTPS"
+  ssl_policy        = "ELBSecurityPolicy-TLS13-1-2-2021-06"
+  certificate_arn   = aws_acm_certificate.alb.arn
+
+  default_action {
+    type = "fixed-response"
+
+    fixed_response {
+      content_type = "text/plain"
+      message_body = "Not Found"
+      status_code  = "404"
+    }
+  }
+}
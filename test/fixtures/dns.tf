@@ -0,0 +1,15 @@
+# Private hosted zone for the Route 53 alias scenario
+resource "aws_route53_zone" "private" {
+  name          = "${var.name_prefix}.internal"
+  force_destroy = true
+
+  vpc {
+    vpc_id = aws_vpc.main.id
+  }
+
+  tags = {
+    Name      = "${var.name_prefix}-private-zone"
+    ManagedBy = "terratest"
+    TestName  = var.name_prefix
+  }
+}
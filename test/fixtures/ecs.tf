@@ -1,65 +1,147 @@
 # ECS Cluster
 resource "aws_ecs_cluster" "main" {
-  name = "terratest-fixtures-cluster"
+  name = "${var.name_prefix}-cluster"
+
+  # RunningTaskCount/DesiredTaskCount for the running_tasks_low alarm
+  setting {
+    name  = "containerInsights"
+    value = "enabled"
+  }
+
+  tags = {
+    Name      = "${var.name_prefix}-cluster"
+    ManagedBy = "terratest"
+    TestName  = var.name_prefix
+  }
+}
+
+# SNS topic for the module alarms
+resource "aws_sns_topic" "alarms" {
+  name = "${var.name_prefix}-alarms"
 
   tags = {
-    Name      = "terratest-fixtures-cluster"
+    Name      = "${var.name_prefix}-alarms"
     ManagedBy = "terratest"
-    TestName  = "terratest-fixtures"
+    TestName  = var.name_prefix
   }
 }
 
 # CloudWatch Log Group
 resource "aws_cloudwatch_log_group" "main" {
-  name              = "/ecs/terratest-fixtures-service"
+  name              = "/ecs/${var.name_prefix}-service"
   retention_in_days = 7
 
   tags = {
-    Name      = "terratest-fixtures-log-group"
+    Name      = "${var.name_prefix}-log-group"
+    ManagedBy = "terratest"
+    TestName  = var.name_prefix
+  }
+}
+
+# KMS key for the module-managed log group scenario (create_log_group)
+resource "aws_kms_key" "logs" {
+  description             = "${var.name_prefix} CloudWatch Logs encryption"
+  deletion_window_in_days = 7
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Sid       = "AccountAdmin"
+        Effect    = "Allow"
+        Principal = { AWS = "arn:aws:iam::${data.aws_caller_identity.current.account_id}:root" }
+        Action    = "kms:*"
+        Resource  = "*"
+      },
+      {
+        Sid       = "CloudWatchLogs"
+        Effect    = "Allow"
+        Principal = { Service = "logs.${var.aws_region}.amazonaws.com" }
+        Action = [
+          "kms:Encrypt*",
+          "kms:Decrypt*",
+          "kms:ReEncrypt*",
+          "kms:GenerateDataKey*",
+          "kms:Describe*",
+        ]
+        Resource = "*"
+        Condition = {
+          ArnLike = {
+            "kms:EncryptionContext:aws:logs:arn" = "arn:aws:logs:${var.aws_region}:${data.aws_caller_identity.current.account_id}:log-group:*"
+          }
+        }
+      },
+    ]
+  })
+
+  tags = {
+    Name      = "${var.name_prefix}-logs-key"
+    ManagedBy = "terratest"
+    TestName  = var.name_prefix
+  }
+}
+
+# S3 Bucket for environment files (environmentFiles)
+# Tests upload their .env files here before applying the module
+resource "aws_s3_bucket" "env_files" {
+  bucket        = "${var.name_prefix}-env-files-${var.aws_region}-${data.aws_caller_identity.current.account_id}"
+  force_destroy = true
+
+  tags = {
+    Name      = "${var.name_prefix}-env-files"
     ManagedBy = "terratest"
-    TestName  = "terratest-fixtures"
+    TestName  = var.name_prefix
   }
 }
 
+resource "aws_s3_bucket_public_access_block" "env_files" {
+  bucket = aws_s3_bucket.env_files.id
+
+  block_public_acls       = true
+  block_public_policy     = true
+  ignore_public_acls      = true
+  restrict_public_buckets = true
+}
+
 # SSM Parameters for secrets
 resource "aws_ssm_parameter" "test_secret" {
-  name        = "/ecs/terratest-fixtures/TEST_SECRET"
+  name        = "/ecs/${var.name_prefix}/TEST_SECRET"
   description = "Test secret for ECS service"
   type        = "SecureString"
   value       = "test-secret-value"
   overwrite   = true
 
   tags = {
-    Name      = "terratest-fixtures-test-secret"
+    Name      = "${var.name_prefix}-test-secret"
     ManagedBy = "terratest"
-    TestName  = "terratest-fixtures"
+    TestName  = var.name_prefix
   }
 }
 
 resource "aws_ssm_parameter" "api_key" {
-  name        = "/ecs/terratest-fixtures/API_KEY"
+  name        = "/ecs/${var.name_prefix}/API_KEY"
   description = "API key secret for ECS service"
   type        = "SecureString"
   value       = "test-api-key-12345"
   overwrite   = true
 
   tags = {
-    Name      = "terratest-fixtures-api-key"
+    Name      = "${var.name_prefix}-api-key"
     ManagedBy = "terratest"
-    TestName  = "terratest-fixtures"
+    TestName  = var.name_prefix
   }
 }
 
 # Secrets Manager secret for testing
 resource "aws_secretsmanager_secret" "database_password" {
-  name                    = "terratest-fixtures-db-password"
+  name                    = "${var.name_prefix}-db-password"
   description             = "Database password for ECS service testing"
   recovery_window_in_days = 0 # Force delete immediately for testing
 
   tags = {
-    Name      = "terratest-fixtures-db-password"
+    Name      = "${var.name_prefix}-db-password"
     ManagedBy = "terratest"
-    TestName  = "terratest-fixtures"
+    TestName  = var.name_prefix
   }
 }
 
@@ -5,6 +5,10 @@ terraform {
       source  = "hashicorp/aws"
       version = "~> 5.0"
     }
+    tls = {
+      source  = "hashicorp/tls"
+      version = "~> 4.0"
+    }
   }
 }
 
@@ -18,6 +22,19 @@ variable "aws_region" {
   default     = "us-east-1"
 }
 
+# Prefijo de nombres y tag TestName, distinto por test para que varias ejecuciones no compartan recursos
+# Máximo 21 caracteres: "<name_prefix>-default-tg" debe caber en los 32 de un target group
+variable "name_prefix" {
+  description = "Prefix for fixture resource names and value of the TestName tag"
+  type        = string
+  default     = "terratest-fixtures"
+
+  validation {
+    condition     = can(regex("^[a-z0-9][a-z0-9-]{0,20}$", var.name_prefix))
+    error_message = "name_prefix must be 1-21 lowercase letters, numbers or hyphens, starting with a letter or number"
+  }
+}
+
 # Get available AZs
 data "aws_availability_zones" "available" {
   state = "available"
@@ -30,9 +47,9 @@ resource "aws_vpc" "main" {
   enable_dns_support   = true
 
   tags = {
-    Name      = "terratest-fixtures-vpc"
+    Name      = "${var.name_prefix}-vpc"
     ManagedBy = "terratest"
-    TestName  = "terratest-fixtures"
+    TestName  = var.name_prefix
   }
 }
 
@@ -41,9 +58,9 @@ resource "aws_internet_gateway" "main" {
   vpc_id = aws_vpc.main.id
 
   tags = {
-    Name      = "terratest-fixtures-igw"
+    Name      = "${var.name_prefix}-igw"
     ManagedBy = "terratest"
-    TestName  = "terratest-fixtures"
+    TestName  = var.name_prefix
   }
 }
 
@@ -57,10 +74,10 @@ resource "aws_subnet" "public" {
   map_public_ip_on_launch = true
 
   tags = {
-    Name      = "terratest-fixtures-public-subnet-${count.index + 1}"
+    Name      = "${var.name_prefix}-public-subnet-${count.index + 1}"
     Type      = "public"
     ManagedBy = "terratest"
-    TestName  = "terratest-fixtures"
+    TestName  = var.name_prefix
   }
 }
 
@@ -72,10 +89,10 @@ resource "aws_subnet" "private" {
   availability_zone = data.aws_availability_zones.available.names[count.index]
 
   tags = {
-    Name      = "terratest-fixtures-private-subnet-${count.index + 1}"
+    Name      = "${var.name_prefix}-private-subnet-${count.index + 1}"
     Type      = "private"
     ManagedBy = "terratest"
-    TestName  = "terratest-fixtures"
+    TestName  = var.name_prefix
   }
 }
 
@@ -85,9 +102,9 @@ resource "aws_eip" "nat" {
   domain = "vpc"
 
   tags = {
-    Name      = "terratest-fixtures-nat-eip-${count.index + 1}"
+    Name      = "${var.name_prefix}-nat-eip-${count.index + 1}"
     ManagedBy = "terratest"
-    TestName  = "terratest-fixtures"
+    TestName  = var.name_prefix
   }
 
   depends_on = [aws_internet_gateway.main]
@@ -100,9 +117,9 @@ resource "aws_nat_gateway" "main" {
   subnet_id     = aws_subnet.public[count.index].id
 
   tags = {
-    Name      = "terratest-fixtures-nat-${count.index + 1}"
+    Name      = "${var.name_prefix}-nat-${count.index + 1}"
     ManagedBy = "terratest"
-    TestName  = "terratest-fixtures"
+    TestName  = var.name_prefix
   }
 
   depends_on = [aws_internet_gateway.main]
@@ -118,9 +135,9 @@ resource "aws_route_table" "public" {
   }
 
   tags = {
-    Name      = "terratest-fixtures-public-rt"
+    Name      = "${var.name_prefix}-public-rt"
     ManagedBy = "terratest"
-    TestName  = "terratest-fixtures"
+    TestName  = var.name_prefix
   }
 }
 
@@ -142,9 +159,9 @@ resource "aws_route_table" "private" {
   }
 
   tags = {
-    Name      = "terratest-fixtures-private-rt-${count.index + 1}"
+    Name      = "${var.name_prefix}-private-rt-${count.index + 1}"
     ManagedBy = "terratest"
-    TestName  = "terratest-fixtures"
+    TestName  = var.name_prefix
   }
 }
 
@@ -154,3 +171,16 @@ resource "aws_route_table_association" "private" {
   subnet_id      = aws_subnet.private[count.index].id
   route_table_id = aws_route_table.private[count.index].id
 }
+
+# Shared Security Group (attached to tasks through additional_security_group_ids)
+resource "aws_security_group" "shared" {
+  name        = "${var.name_prefix}-shared-sg"
+  description = "Shared security group for test ECS tasks"
+  vpc_id      = aws_vpc.main.id
+
+  tags = {
+    Name      = "${var.name_prefix}-shared-sg"
+    ManagedBy = "terratest"
+    TestName  = var.name_prefix
+  }
+}
@@ -0,0 +1,44 @@
+# Security Group for NLB
+resource "aws_security_group" "nlb" {
+  name        = "${var.name_prefix}-nlb-sg"
+  description = "Security group for test NLB"
+  vpc_id      = aws_vpc.main.id
+
+  ingress {
+    from_port   = 8080
+    to_port     = 8080
+    protocol    = "tcp"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  egress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = {
+    Name      = "${var.name_prefix}-nlb-sg"
+    ManagedBy = "terratest"
+    TestName  = var.name_prefix
+  }
+}
+
+# Network Load Balancer
+# Listeners and target groups are created by the module scenarios (nlb.listener)
+resource "aws_lb" "nlb" {
+  name               = "${var.name_prefix}-nlb"
+  internal           = false
+  load_balancer_type = "network"
+  security_groups    = [aws_security_group.nlb.id]
+  subnets            = aws_subnet.public[*].id
+
+  enable_deletion_protection = false
+
+  tags = {
+    Name      = "${var.name_prefix}-nlb"
+    ManagedBy = "terratest"
+    TestName  = var.name_prefix
+  }
+}
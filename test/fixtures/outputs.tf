@@ -18,6 +18,11 @@ output "public_subnet_ids" {
   value       = aws_subnet.public[*].id
 }
 
+output "shared_security_group_id" {
+  description = "ID of the shared security group for ECS tasks"
+  value       = aws_security_group.shared.id
+}
+
 output "alb_load_balancer_arn" {
   description = "ARN of the ALB load balancer"
   value       = aws_lb.main.arn
@@ -28,6 +33,11 @@ output "alb_listener_arn" {
   value       = aws_lb_listener.main.arn
 }
 
+output "alb_https_listener_arn" {
+  description = "ARN of the ALB HTTPS listener (self-signed certificate)"
+  value       = aws_lb_listener.https.arn
+}
+
 output "alb_security_group_id" {
   description = "ID of the ALB security group"
   value       = aws_security_group.alb.id
@@ -62,3 +72,53 @@ output "database_password_arn" {
   description = "ARN of the DATABASE_PASSWORD Secrets Manager secret"
   value       = aws_secretsmanager_secret.database_password.arn
 }
+
+output "secret_values" {
+  description = "Values of the TEST_SECRET, API_KEY and DATABASE_PASSWORD secrets, by variable name"
+  value = {
+    TEST_SECRET       = aws_ssm_parameter.test_secret.value
+    API_KEY           = aws_ssm_parameter.api_key.value
+    DATABASE_PASSWORD = aws_secretsmanager_secret_version.database_password.secret_string
+  }
+  sensitive = true
+}
+
+output "env_files_bucket_name" {
+  description = "Name of the S3 bucket used to store environment files"
+  value       = aws_s3_bucket.env_files.id
+}
+
+output "nlb_load_balancer_arn" {
+  description = "ARN of the Network Load Balancer"
+  value       = aws_lb.nlb.arn
+}
+
+output "nlb_dns_name" {
+  description = "DNS name of the Network Load Balancer"
+  value       = aws_lb.nlb.dns_name
+}
+
+output "nlb_security_group_id" {
+  description = "ID of the NLB security group"
+  value       = aws_security_group.nlb.id
+}
+
+output "private_zone_id" {
+  description = "ID of the private Route 53 hosted zone"
+  value       = aws_route53_zone.private.zone_id
+}
+
+output "private_zone_name" {
+  description = "Name of the private Route 53 hosted zone"
+  value       = aws_route53_zone.private.name
+}
+
+output "alarms_topic_arn" {
+  description = "ARN of the SNS topic for the module alarms"
+  value       = aws_sns_topic.alarms.arn
+}
+
+output "logs_kms_key_arn" {
+  description = "ARN of the KMS key for module-managed log groups"
+  value       = aws_kms_key.logs.arn
+}
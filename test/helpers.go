@@ -1,18 +1,39 @@
 package test
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/scheduler"
+	"github.com/aws/aws-sdk-go/service/sts"
 	terratestaws "github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
 )
 
 // InfrastructureOutputs contains outputs from the infrastructure fixtures
@@ -23,7 +44,16 @@ type InfrastructureOutputs struct {
 	PublicSubnetIDs        []string
 	ALBLoadBalancerARN     string // Optional - empty if ALB is not configured
 	ALBListenerARN         string // Optional - empty if ALB is not configured
+	ALBHTTPSListenerARN    string // Optional - empty if ALB is not configured
 	ALBSecurityGroupID     string // Optional - empty if ALB is not configured
+	NLBLoadBalancerARN     string // Optional - empty if NLB is not configured
+	NLBDNSName             string // Optional - empty if NLB is not configured
+	NLBSecurityGroupID     string // Optional - empty if NLB is not configured
+	SharedSecurityGroupID  string
+	PrivateZoneID          string // Optional - empty if the private hosted zone is not configured
+	AlarmsTopicARN         string // Optional - empty if the alarms SNS topic is not configured
+	LogsKMSKeyARN          string // Optional - empty if the logs KMS key is not configured
+	PrivateZoneName        string // Optional - empty if the private hosted zone is not configured
 	ServiceDiscoveryNSID   string // Optional - namespace ID for service discovery
 	ClusterName            string
 	CloudWatchLogGroupName string
@@ -31,16 +61,36 @@ type InfrastructureOutputs struct {
 	TestSecretARN          string
 	APIKeyARN              string
 	DatabasePasswordARN    string
+	SecretValues           map[string]string // Sensitive - secret values by variable name, never logged
+	EnvFilesBucketName     string
+}
+
+// bootstrapResources are the state bucket and lock table shared by every test run
+// They are applied without backend first and never destroyed by a single test
+var bootstrapResources = []string{
+	"aws_s3_bucket.terraform_state",
+	"aws_s3_bucket_versioning.terraform_state",
+	"aws_s3_bucket_server_side_encryption_configuration.terraform_state",
+	"aws_s3_bucket_public_access_block.terraform_state",
+	"aws_dynamodb_table.terraform_locks",
 }
 
 // setupInfrastructure applies the infrastructure fixtures and returns outputs
 // Returns (options, outputs, error) - options is always returned so cleanup can run even if there are errors
 func setupInfrastructure(t *testing.T, testName string) (*terraform.Options, *InfrastructureOutputs, error) {
-	fixturesDir := "../test/fixtures"
 	awsRegion := getAWSRegion()
 
+	// Each test gets its own copy of the fixtures (local bootstrap state and .terraform),
+	// its own resource names (name_prefix) and its own state key, so runs can overlap
+	namePrefix := testName
+	fixturesDir, err := files.CopyTerraformFolderToTemp("fixtures", namePrefix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to copy fixtures: %w", err)
+	}
+
 	t.Logf("🏗️  Setting up infrastructure base...")
 	t.Logf("   Test Name: %s", testName)
+	t.Logf("   Name Prefix: %s", namePrefix)
 	t.Logf("   AWS Region: %s", awsRegion)
 	t.Logf("   Fixtures Directory: %s", fixturesDir)
 
@@ -58,14 +108,16 @@ func setupInfrastructure(t *testing.T, testName string) (*terraform.Options, *In
 		TerraformBinary: "terraform",
 		BackendConfig: map[string]interface{}{
 			"bucket":         bucketName,
-			"key":            "fixtures/terraform.tfstate",
+			"key":            fmt.Sprintf("fixtures/%s/terraform.tfstate", namePrefix),
 			"region":         awsRegion,
 			"dynamodb_table": dynamoTableName,
 			"encrypt":        true,
 		},
 		Vars: map[string]interface{}{
-			"aws_region": awsRegion,
+			"aws_region":  awsRegion,
+			"name_prefix": namePrefix,
 		},
+		EnvVars: terraformEnvVars(),
 		NoColor: true,
 	}
 
@@ -77,16 +129,12 @@ func setupInfrastructure(t *testing.T, testName string) (*terraform.Options, *In
 		TerraformBinary: "terraform",
 		// No BackendConfig means Terraform will use local state
 		Vars: map[string]interface{}{
-			"aws_region": awsRegion,
+			"aws_region":  awsRegion,
+			"name_prefix": namePrefix,
 		},
+		EnvVars: terraformEnvVars(),
 		NoColor: true,
-		Targets: []string{
-			"aws_s3_bucket.terraform_state",
-			"aws_s3_bucket_versioning.terraform_state",
-			"aws_s3_bucket_server_side_encryption_configuration.terraform_state",
-			"aws_s3_bucket_public_access_block.terraform_state",
-			"aws_dynamodb_table.terraform_locks",
-		},
+		Targets: bootstrapResources,
 	}
 
 	// Initialize without backend and apply bootstrap resources
@@ -140,7 +188,7 @@ func setupInfrastructure(t *testing.T, testName string) (*terraform.Options, *In
 
 	// Use ApplyE to handle errors gracefully (e.g., S3 bucket already exists)
 	// The DynamoDB table is shared across all tests, so it may already exist
-	_, err := terraform.ApplyE(t, bootstrapOptions)
+	_, err = terraform.ApplyE(t, bootstrapOptions)
 	if err != nil {
 		// Check if error is due to resource already existing
 		errStr := strings.ToLower(err.Error())
@@ -237,12 +285,66 @@ func setupInfrastructure(t *testing.T, testName string) (*terraform.Options, *In
 		t.Logf("⚠️  Could not read alb_listener_arn output: %v", err)
 	}
 
+	if albHTTPSListenerARN, err := terraform.OutputE(t, terraformOptions, "alb_https_listener_arn"); err == nil {
+		outputs.ALBHTTPSListenerARN = albHTTPSListenerARN
+	} else {
+		t.Logf("⚠️  Could not read alb_https_listener_arn output: %v", err)
+	}
+
 	if albSGID, err := terraform.OutputE(t, terraformOptions, "alb_security_group_id"); err == nil {
 		outputs.ALBSecurityGroupID = albSGID
 	} else {
 		t.Logf("⚠️  Could not read alb_security_group_id output: %v", err)
 	}
 
+	if nlbLoadBalancerARN, err := terraform.OutputE(t, terraformOptions, "nlb_load_balancer_arn"); err == nil {
+		outputs.NLBLoadBalancerARN = nlbLoadBalancerARN
+	} else {
+		t.Logf("⚠️  Could not read nlb_load_balancer_arn output: %v", err)
+	}
+
+	if nlbDNSName, err := terraform.OutputE(t, terraformOptions, "nlb_dns_name"); err == nil {
+		outputs.NLBDNSName = nlbDNSName
+	} else {
+		t.Logf("⚠️  Could not read nlb_dns_name output: %v", err)
+	}
+
+	if nlbSGID, err := terraform.OutputE(t, terraformOptions, "nlb_security_group_id"); err == nil {
+		outputs.NLBSecurityGroupID = nlbSGID
+	} else {
+		t.Logf("⚠️  Could not read nlb_security_group_id output: %v", err)
+	}
+
+	if privateZoneID, err := terraform.OutputE(t, terraformOptions, "private_zone_id"); err == nil {
+		outputs.PrivateZoneID = privateZoneID
+	} else {
+		t.Logf("⚠️  Could not read private_zone_id output: %v", err)
+	}
+
+	if privateZoneName, err := terraform.OutputE(t, terraformOptions, "private_zone_name"); err == nil {
+		outputs.PrivateZoneName = privateZoneName
+	} else {
+		t.Logf("⚠️  Could not read private_zone_name output: %v", err)
+	}
+
+	if alarmsTopicARN, err := terraform.OutputE(t, terraformOptions, "alarms_topic_arn"); err == nil {
+		outputs.AlarmsTopicARN = alarmsTopicARN
+	} else {
+		t.Logf("⚠️  Could not read alarms_topic_arn output: %v", err)
+	}
+
+	if logsKMSKeyARN, err := terraform.OutputE(t, terraformOptions, "logs_kms_key_arn"); err == nil {
+		outputs.LogsKMSKeyARN = logsKMSKeyARN
+	} else {
+		t.Logf("⚠️  Could not read logs_kms_key_arn output: %v", err)
+	}
+
+	if sharedSGID, err := terraform.OutputE(t, terraformOptions, "shared_security_group_id"); err == nil {
+		outputs.SharedSecurityGroupID = sharedSGID
+	} else {
+		t.Logf("⚠️  Could not read shared_security_group_id output: %v", err)
+	}
+
 	if clusterName, err := terraform.OutputE(t, terraformOptions, "cluster_name"); err == nil {
 		outputs.ClusterName = clusterName
 	} else {
@@ -273,6 +375,21 @@ func setupInfrastructure(t *testing.T, testName string) (*terraform.Options, *In
 		t.Logf("⚠️  Could not read database_password_arn output: %v", err)
 	}
 
+	// terraform output echoes what it prints, so the sensitive values are read without logging
+	quietOptions := *terraformOptions
+	quietOptions.Logger = logger.Discard
+	if secretValues, err := terraform.OutputMapE(t, &quietOptions, "secret_values"); err == nil {
+		outputs.SecretValues = secretValues
+	} else {
+		t.Logf("⚠️  Could not read secret_values output: %v", err)
+	}
+
+	if envFilesBucketName, err := terraform.OutputE(t, terraformOptions, "env_files_bucket_name"); err == nil {
+		outputs.EnvFilesBucketName = envFilesBucketName
+	} else {
+		t.Logf("⚠️  Could not read env_files_bucket_name output: %v", err)
+	}
+
 	t.Logf("✅ Infrastructure outputs retrieved:")
 	// Helper function to format output values, showing "(not available)" if empty
 	formatOutput := func(value string) string {
@@ -296,12 +413,21 @@ func setupInfrastructure(t *testing.T, testName string) (*terraform.Options, *In
 	}
 	t.Logf("   ALB Load Balancer ARN: %s", formatOutput(outputs.ALBLoadBalancerARN))
 	t.Logf("   ALB Listener ARN: %s", formatOutput(outputs.ALBListenerARN))
+	t.Logf("   ALB HTTPS Listener ARN: %s", formatOutput(outputs.ALBHTTPSListenerARN))
 	t.Logf("   ALB Security Group ID: %s", formatOutput(outputs.ALBSecurityGroupID))
+	t.Logf("   NLB Load Balancer ARN: %s", formatOutput(outputs.NLBLoadBalancerARN))
+	t.Logf("   NLB DNS Name: %s", formatOutput(outputs.NLBDNSName))
+	t.Logf("   NLB Security Group ID: %s", formatOutput(outputs.NLBSecurityGroupID))
+	t.Logf("   Shared Security Group ID: %s", formatOutput(outputs.SharedSecurityGroupID))
+	t.Logf("   Private Zone: %s (%s)", formatOutput(outputs.PrivateZoneName), formatOutput(outputs.PrivateZoneID))
+	t.Logf("   Alarms Topic ARN: %s", formatOutput(outputs.AlarmsTopicARN))
+	t.Logf("   Logs KMS Key ARN: %s", formatOutput(outputs.LogsKMSKeyARN))
 	t.Logf("   Cluster Name: %s", formatOutput(outputs.ClusterName))
 	t.Logf("   Log Group Name: %s", formatOutput(outputs.CloudWatchLogGroupName))
 	t.Logf("   Test Secret ARN: %s", formatOutput(outputs.TestSecretARN))
 	t.Logf("   API Key ARN: %s", formatOutput(outputs.APIKeyARN))
 	t.Logf("   Database Password ARN: %s", formatOutput(outputs.DatabasePasswordARN))
+	t.Logf("   Env Files Bucket: %s", formatOutput(outputs.EnvFilesBucketName))
 
 	// Validate that critical outputs are present before continuing
 	validateInfrastructureOutputs(t, outputs)
@@ -325,6 +451,9 @@ func validateInfrastructureOutputs(t *testing.T, outputs *InfrastructureOutputs)
 	}
 	// ALB outputs are optional - only validate if they're being used
 	// (This will be determined by the test configuration)
+	if outputs.SharedSecurityGroupID == "" {
+		missingOutputs = append(missingOutputs, "shared_security_group_id")
+	}
 	if outputs.ClusterName == "" {
 		missingOutputs = append(missingOutputs, "cluster_name")
 	}
@@ -340,6 +469,9 @@ func validateInfrastructureOutputs(t *testing.T, outputs *InfrastructureOutputs)
 	if outputs.DatabasePasswordARN == "" {
 		missingOutputs = append(missingOutputs, "database_password_arn")
 	}
+	if outputs.EnvFilesBucketName == "" {
+		missingOutputs = append(missingOutputs, "env_files_bucket_name")
+	}
 
 	if len(missingOutputs) > 0 {
 		t.Errorf("❌ Critical infrastructure outputs are missing: %v", missingOutputs)
@@ -371,7 +503,15 @@ func teardownInfrastructure(t *testing.T, terraformOptions *terraform.Options) {
 	// CRITICAL: Always run destroy, even if there were errors during apply
 	// This ensures resources are cleaned up and don't become orphaned
 	t.Logf("⚠️  IMPORTANT: Running destroy to ensure all resources are cleaned up")
-	
+
+	// The state bucket and lock table are shared with runs that may still be in progress:
+	// drop them from this test's state so destroy leaves them in place
+	for _, address := range bootstrapResources {
+		if _, err := terraform.RunTerraformCommandE(t, terraformOptions, "state", "rm", address); err != nil {
+			t.Logf("   %s not in state, nothing to keep", address)
+		}
+	}
+
 	// Use DestroyE to handle errors gracefully
 	// This allows cleanup to continue even if there are issues
 	_, err := terraform.DestroyE(t, terraformOptions)
@@ -392,7 +532,7 @@ func teardownInfrastructure(t *testing.T, terraformOptions *terraform.Options) {
 		} else {
 			t.Logf("⚠️  Warning: Error during infrastructure teardown: %v", err)
 			t.Logf("   Resources may need manual cleanup")
-			t.Logf("   Run: cd test && ./cleanup-orphaned-resources.sh")
+			t.Logf("   Run: cd test && go run ./sweeper -test-name %s -ttl 0", terraformOptions.Vars["name_prefix"])
 			if bucket, ok := terraformOptions.BackendConfig["bucket"].(string); ok {
 				t.Logf("   State bucket: %s", bucket)
 			}
@@ -441,6 +581,8 @@ func setupModuleOptions(t *testing.T, moduleDir string, outputs *InfrastructureO
 	if tag := os.Getenv("IMAGE_TAG"); tag != "" {
 		imageTag = tag
 	}
+	// Pin the tag to its sha256 digest (only supported for ECR images)
+	resolveImageDigest := os.Getenv("RESOLVE_IMAGE_DIGEST") == "true"
 
 	containerPort := 80
 	if port := os.Getenv("CONTAINER_PORT"); port != "" {
@@ -454,7 +596,8 @@ func setupModuleOptions(t *testing.T, moduleDir string, outputs *InfrastructureO
 		"service_name":              fmt.Sprintf("%s-service", testName),
 		"docker_image":              dockerImage,
 		"image_tag":                 imageTag,
-		"container_port":           containerPort,
+		"resolve_image_digest":      resolveImageDigest,
+		"container_port":            containerPort,
 		"task_cpu":                  "256",
 		"task_memory":               "512",
 		"subnet_ids":                outputs.PrivateSubnetIDs,
@@ -500,9 +643,17 @@ func setupModuleOptions(t *testing.T, moduleDir string, outputs *InfrastructureO
 			"Project":     "terratest",
 			"Environment": "test",
 			"TestName":    testName,
+			"ManagedBy":   "terratest",
 		},
 	}
 
+	if outputs.AlarmsTopicARN != "" {
+		vars["alarms"] = map[string]interface{}{
+			"sns_topic_arns": []string{outputs.AlarmsTopicARN},
+			"dashboard":      true,
+		}
+	}
+
 	// Add ALB-related variables only if ALB is configured
 	if outputs.ALBLoadBalancerARN != "" && outputs.ALBSecurityGroupID != "" {
 		vars["alb_load_balancer_arn"] = outputs.ALBLoadBalancerARN
@@ -527,9 +678,10 @@ func setupModuleOptions(t *testing.T, moduleDir string, outputs *InfrastructureO
 	}
 
 	return &terraform.Options{
-		TerraformDir:    moduleDir,
-		TerraformBinary: "terraform",
-		Vars:            vars,
+		TerraformDir:       moduleDir,
+		TerraformBinary:    "terraform",
+		Vars:               vars,
+		EnvVars:            terraformEnvVars(),
 		NoColor:            true,
 		MaxRetries:         3,
 		TimeBetweenRetries: 5 * time.Second,
@@ -539,6 +691,585 @@ func setupModuleOptions(t *testing.T, moduleDir string, outputs *InfrastructureO
 	}
 }
 
+// scenarioListenerPriority hands out listener rule priorities to scenario deployments
+// so they don't collide with the main module run (priority 100) on the shared listener
+var scenarioListenerPriority int32 = 200
+
+// nextScenarioListenerPriority returns a listener rule priority not used by any other scenario
+func nextScenarioListenerPriority() int32 {
+	return atomic.AddInt32(&scenarioListenerPriority, 1)
+}
+
+// setupScenarioOptions returns the module options for a scenario deployed from moduleDir
+// next to the main run, with its own listener rule on the shared listener
+func setupScenarioOptions(t *testing.T, moduleDir string, outputs *InfrastructureOutputs, scenarioName string) *terraform.Options {
+	scenarioOptions := setupModuleOptions(t, moduleDir, outputs, scenarioName)
+
+	// Route only the scenario path to its own target group on the shared listener
+	if _, ok := scenarioOptions.Vars["listener_rules"]; ok {
+		scenarioOptions.Vars["listener_rules"] = []map[string]interface{}{
+			{
+				"priority":      nextScenarioListenerPriority(),
+				"path_patterns": []string{fmt.Sprintf("/%s/*", scenarioName)},
+			},
+		}
+	}
+	return scenarioOptions
+}
+
+// deployModuleScenario applies an additional copy of the module against the shared fixtures
+// The module is copied to a temporary folder so its local state doesn't collide with the main run
+// customize receives the default vars from setupModuleOptions and may change them before apply
+// Cleanup is registered before applying, so resources are destroyed even if apply fails
+func deployModuleScenario(t *testing.T, outputs *InfrastructureOutputs, scenarioName string, customize func(vars map[string]interface{})) (*terraform.Options, error) {
+	moduleDir, err := files.CopyTerraformFolderToTemp("..", scenarioName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy module for scenario %s: %w", scenarioName, err)
+	}
+	scenarioOptions := setupScenarioOptions(t, moduleDir, outputs, scenarioName)
+
+	if customize != nil {
+		customize(scenarioOptions.Vars)
+	}
+
+	t.Cleanup(func() {
+		cleanupModule(t, scenarioOptions)
+	})
+
+	t.Logf("🚀 Applying Terraform module for scenario %s...", scenarioName)
+	t.Logf("   Module Directory: %s", moduleDir)
+	if _, err := terraform.InitAndApplyE(t, scenarioOptions); err != nil {
+		t.Logf("❌ Error applying scenario %s: %v", scenarioName, err)
+		return scenarioOptions, err
+	}
+	t.Logf("✅ Scenario %s applied successfully", scenarioName)
+
+	t.Logf("🔁 Checking that a second plan of scenario %s is empty...", scenarioName)
+	if err := checkEmptyPlan(t, scenarioOptions); err != nil {
+		t.Logf("❌ Scenario %s does not converge: %v", scenarioName, err)
+		return scenarioOptions, err
+	}
+
+	return scenarioOptions, nil
+}
+
+// planModule runs terraform plan on a copy of terraformOptions and returns the parsed plan
+// The plan file lives in a temp dir, so terraformOptions can still be used for apply/destroy afterwards
+func planModule(t *testing.T, terraformOptions *terraform.Options) (*terraform.PlanStruct, error) {
+	planOptions, err := terraformOptions.Clone()
+	if err != nil {
+		return nil, err
+	}
+	planOptions.PlanFilePath = filepath.Join(t.TempDir(), "tfplan")
+
+	if _, err := terraform.PlanE(t, planOptions); err != nil {
+		return nil, err
+	}
+	return terraform.ShowWithStructE(t, planOptions)
+}
+
+// checkEmptyPlan runs terraform plan right after an apply and returns an error listing the drifting
+// attributes if the module does not converge (a perpetual diff would redeploy the service on every run)
+func checkEmptyPlan(t *testing.T, terraformOptions *terraform.Options) error {
+	plan, err := planModule(t, terraformOptions)
+	if err != nil {
+		return fmt.Errorf("failed to plan after apply: %w", err)
+	}
+
+	var drift []string
+	for _, change := range plan.RawPlan.ResourceChanges {
+		actions := change.Change.Actions
+		if actions.NoOp() || actions.Read() {
+			continue
+		}
+		attributes := diffPlanValues("", change.Change.Before, change.Change.After, change.Change.AfterUnknown)
+		drift = append(drift, fmt.Sprintf("%s %v:\n      %s", change.Address, actions, strings.Join(attributes, "\n      ")))
+	}
+	if len(drift) == 0 {
+		return nil
+	}
+	sort.Strings(drift)
+	return fmt.Errorf("plan after apply is not empty:\n  %s", strings.Join(drift, "\n  "))
+}
+
+// diffPlanValues returns "path: before -> after" for every attribute that differs between two plan values
+// JSON strings (container_definitions, policies) are decoded so the exact drifting field is reported
+func diffPlanValues(path string, before, after, afterUnknown interface{}) []string {
+	if unknown, ok := afterUnknown.(bool); ok && unknown {
+		return []string{fmt.Sprintf("%s: %s -> (known after apply)", planPath(path), formatPlanValue(before))}
+	}
+
+	if beforeString, ok := before.(string); ok {
+		if afterString, ok := after.(string); ok && beforeString != afterString {
+			var beforeJSON, afterJSON interface{}
+			if json.Unmarshal([]byte(beforeString), &beforeJSON) == nil && json.Unmarshal([]byte(afterString), &afterJSON) == nil {
+				if _, isString := beforeJSON.(string); !isString {
+					if diff := diffPlanValues(path, beforeJSON, afterJSON, nil); len(diff) > 0 {
+						return diff
+					}
+					return []string{fmt.Sprintf("%s: JSON formatting only", planPath(path))}
+				}
+			}
+		}
+	}
+
+	beforeMap, beforeIsMap := before.(map[string]interface{})
+	afterMap, afterIsMap := after.(map[string]interface{})
+	if beforeIsMap && afterIsMap {
+		unknownMap, _ := afterUnknown.(map[string]interface{})
+		keys := make(map[string]bool)
+		for key := range beforeMap {
+			keys[key] = true
+		}
+		for key := range afterMap {
+			keys[key] = true
+		}
+		sortedKeys := make([]string, 0, len(keys))
+		for key := range keys {
+			sortedKeys = append(sortedKeys, key)
+		}
+		sort.Strings(sortedKeys)
+
+		var diff []string
+		for _, key := range sortedKeys {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			diff = append(diff, diffPlanValues(childPath, beforeMap[key], afterMap[key], unknownMap[key])...)
+		}
+		return diff
+	}
+
+	beforeList, beforeIsList := before.([]interface{})
+	afterList, afterIsList := after.([]interface{})
+	if beforeIsList && afterIsList && len(beforeList) == len(afterList) {
+		unknownList, _ := afterUnknown.([]interface{})
+		var diff []string
+		for i := range beforeList {
+			var unknown interface{}
+			if i < len(unknownList) {
+				unknown = unknownList[i]
+			}
+			diff = append(diff, diffPlanValues(fmt.Sprintf("%s[%d]", path, i), beforeList[i], afterList[i], unknown)...)
+		}
+		return diff
+	}
+
+	if reflect.DeepEqual(before, after) {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s: %s -> %s", planPath(path), formatPlanValue(before), formatPlanValue(after))}
+}
+
+// planPath names the whole resource when the difference is at the top level (create/delete)
+func planPath(path string) string {
+	if path == "" {
+		return "(resource)"
+	}
+	return path
+}
+
+// formatPlanValue renders a plan value as compact JSON, truncated for readability
+func formatPlanValue(value interface{}) string {
+	rendered, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	if len(rendered) > 200 {
+		return string(rendered[:200]) + "..."
+	}
+	return string(rendered)
+}
+
+// uploadEnvironmentFile uploads a .env file to the fixtures bucket and returns its S3 object ARN
+// The object is deleted when the test finishes
+func uploadEnvironmentFile(t *testing.T, region, bucketName, key string, variables map[string]string) string {
+	var content strings.Builder
+	for name, value := range variables {
+		content.WriteString(fmt.Sprintf("%s=%s\n", name, value))
+	}
+
+	s3Client := newS3Client(t, region)
+	_, err := s3Client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+		Body:   strings.NewReader(content.String()),
+	})
+	require.NoError(t, err, "Failed to upload environment file s3://%s/%s", bucketName, key)
+
+	t.Cleanup(func() {
+		if _, err := s3Client.DeleteObject(&s3.DeleteObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(key),
+		}); err != nil {
+			t.Logf("⚠️  Could not delete environment file s3://%s/%s: %v", bucketName, key, err)
+		}
+	})
+
+	return fmt.Sprintf("arn:aws:s3:::%s/%s", bucketName, key)
+}
+
+// waitForLogEvent polls CloudWatch Logs until an event matching filterPattern shows up
+// in a stream starting with streamPrefix, and returns the message of the first match
+func waitForLogEvent(t *testing.T, region, logGroupName, streamPrefix, filterPattern string) (string, error) {
+	logsClient := newCloudWatchLogsClient(t, region)
+
+	return retry.DoWithRetryE(t, fmt.Sprintf("Waiting for log event %s in %s", filterPattern, logGroupName), 40, 15*time.Second, func() (string, error) {
+		events, err := logsClient.FilterLogEvents(&cloudwatchlogs.FilterLogEventsInput{
+			LogGroupName:        aws.String(logGroupName),
+			LogStreamNamePrefix: aws.String(streamPrefix),
+			FilterPattern:       aws.String(filterPattern),
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(events.Events) == 0 {
+			return "", fmt.Errorf("no log events matching %s yet", filterPattern)
+		}
+		return aws.StringValue(events.Events[0].Message), nil
+	})
+}
+
+// TEST_BACKEND=localstack runs the suite against a LocalStack endpoint instead of a real AWS account
+// Terraform (fixtures and module) and the SDK clients below follow the same switch
+const localStackBackend = "localstack"
+
+// isLocalStack reports whether the suite runs against LocalStack
+func isLocalStack() bool {
+	return os.Getenv("TEST_BACKEND") == localStackBackend
+}
+
+// localStackEndpoint returns the LocalStack edge endpoint (LOCALSTACK_ENDPOINT, default http://localhost.localstack.cloud:4566)
+// The localhost.localstack.cloud domain resolves to 127.0.0.1, so virtual-hosted S3 bucket URLs work too
+func localStackEndpoint() string {
+	if endpoint := os.Getenv("LOCALSTACK_ENDPOINT"); endpoint != "" {
+		return endpoint
+	}
+	return "http://localhost.localstack.cloud:4566"
+}
+
+// terraformEnvVars returns the environment for terraform commands: the AWS provider, the S3 backend
+// and the AWS CLI (migration script) read AWS_ENDPOINT_URL, so LocalStack needs no change in the .tf files
+func terraformEnvVars() map[string]string {
+	if !isLocalStack() {
+		return nil
+	}
+	endpoint := localStackEndpoint()
+	return map[string]string{
+		"AWS_ENDPOINT_URL": endpoint,
+		// The S3 backend of Terraform 1.6 reads the per-service variables
+		"AWS_ENDPOINT_URL_S3":       endpoint,
+		"AWS_ENDPOINT_URL_DYNAMODB": endpoint,
+		"AWS_ENDPOINT_URL_IAM":      endpoint,
+		"AWS_ENDPOINT_URL_STS":      endpoint,
+		"AWS_ACCESS_KEY_ID":         "test",
+		"AWS_SECRET_ACCESS_KEY":     "test",
+	}
+}
+
+// skipIfLocalStack skips the current (sub)test when the suite runs against LocalStack
+func skipIfLocalStack(t *testing.T, reason string) {
+	if isLocalStack() {
+		t.Skipf("⏭️  Skipping on LocalStack: %s", reason)
+	}
+}
+
+// newAWSSession creates the session used by every SDK client in the suite (AWS or LocalStack)
+func newAWSSession(t *testing.T, region string) *session.Session {
+	if !isLocalStack() {
+		sess, err := terratestaws.NewAuthenticatedSession(region)
+		require.NoError(t, err, "Failed to create AWS session")
+		return sess
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:           aws.String(region),
+		Endpoint:         aws.String(localStackEndpoint()),
+		Credentials:      credentials.NewStaticCredentials("test", "test", ""),
+		S3ForcePathStyle: aws.Bool(true),
+	})
+	require.NoError(t, err, "Failed to create LocalStack session")
+	return sess
+}
+
+// newECSClient creates an ECS client (replaces terratest's NewEcsClient, which always targets AWS)
+func newECSClient(t *testing.T, region string) *ecs.ECS {
+	return ecs.New(newAWSSession(t, region))
+}
+
+// newEC2Client creates an EC2 client (replaces terratest's NewEc2Client, which always targets AWS)
+func newEC2Client(t *testing.T, region string) *ec2.EC2 {
+	return ec2.New(newAWSSession(t, region))
+}
+
+// newIAMClient creates an IAM client (replaces terratest's NewIamClient, which always targets AWS)
+func newIAMClient(t *testing.T, region string) *iam.IAM {
+	return iam.New(newAWSSession(t, region))
+}
+
+// newS3Client creates an S3 client (replaces terratest's NewS3Client, which always targets AWS)
+func newS3Client(t *testing.T, region string) *s3.S3 {
+	return s3.New(newAWSSession(t, region))
+}
+
+// newDynamoDBClient creates a DynamoDB client (replaces terratest's NewDynamoDBClient, which always targets AWS)
+func newDynamoDBClient(t *testing.T, region string) *dynamodb.DynamoDB {
+	return dynamodb.New(newAWSSession(t, region))
+}
+
+// newCloudWatchLogsClient creates a CloudWatch Logs client (replaces terratest's NewCloudWatchLogsClient, which always targets AWS)
+func newCloudWatchLogsClient(t *testing.T, region string) *cloudwatchlogs.CloudWatchLogs {
+	return cloudwatchlogs.New(newAWSSession(t, region))
+}
+
+// newELBv2Client creates an Elastic Load Balancing v2 client (Terratest doesn't provide one)
+func newELBv2Client(t *testing.T, region string) *elbv2.ELBV2 {
+	return elbv2.New(newAWSSession(t, region))
+}
+
+// newCloudWatchClient creates a CloudWatch client (terratest only has one for CloudWatch Logs)
+func newCloudWatchClient(t *testing.T, region string) *cloudwatch.CloudWatch {
+	return cloudwatch.New(newAWSSession(t, region))
+}
+
+// newRoute53Client creates a Route 53 client (terratest has no helper for it)
+func newRoute53Client(t *testing.T, region string) *route53.Route53 {
+	return route53.New(newAWSSession(t, region))
+}
+
+// newSchedulerClient creates an EventBridge Scheduler client (terratest has no helper for it)
+func newSchedulerClient(t *testing.T, region string) *scheduler.Scheduler {
+	return scheduler.New(newAWSSession(t, region))
+}
+
+// getECSService describes a single ECS service (replaces terratest's GetEcsService, which always targets AWS)
+func getECSService(t *testing.T, region, clusterName, serviceName string) *ecs.Service {
+	services, err := newECSClient(t, region).DescribeServices(&ecs.DescribeServicesInput{
+		Cluster:  aws.String(clusterName),
+		Services: []*string{aws.String(serviceName)},
+	})
+	require.NoError(t, err)
+	require.Len(t, services.Services, 1, "Service %s should exist in cluster %s", serviceName, clusterName)
+	return services.Services[0]
+}
+
+// serviceAvailabilityZoneRebalancingOutput is the subset of the DescribeServices response read by
+// getServiceAvailabilityZoneRebalancing: ecs.Service in aws-sdk-go v1 has no availabilityZoneRebalancing field
+type serviceAvailabilityZoneRebalancingOutput struct {
+	_        struct{} `type:"structure"`
+	Services []*struct {
+		_                           struct{} `type:"structure"`
+		AvailabilityZoneRebalancing *string  `locationName:"availabilityZoneRebalancing" type:"string"`
+	} `locationName:"services" type:"list"`
+}
+
+// getServiceAvailabilityZoneRebalancing returns the service's availabilityZoneRebalancing ("ENABLED" or "DISABLED")
+func getServiceAvailabilityZoneRebalancing(t *testing.T, region, clusterName, serviceName string) (string, error) {
+	ecsClient := newECSClient(t, region)
+	output := &serviceAvailabilityZoneRebalancingOutput{}
+	req := ecsClient.NewRequest(&request.Operation{
+		Name:       "DescribeServices",
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}, &ecs.DescribeServicesInput{
+		Cluster:  aws.String(clusterName),
+		Services: []*string{aws.String(serviceName)},
+	}, output)
+	if err := req.Send(); err != nil {
+		return "", err
+	}
+	if len(output.Services) != 1 {
+		return "", fmt.Errorf("service %s not found in cluster %s", serviceName, clusterName)
+	}
+	return aws.StringValue(output.Services[0].AvailabilityZoneRebalancing), nil
+}
+
+// waitForHealthyTargets polls the target group until at least one target is healthy and none is unhealthy
+func waitForHealthyTargets(t *testing.T, region, targetGroupARN string) error {
+	elbClient := newELBv2Client(t, region)
+
+	_, err := retry.DoWithRetryE(t, fmt.Sprintf("Waiting for healthy targets in %s", targetGroupARN), 40, 15*time.Second, func() (string, error) {
+		health, err := elbClient.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{
+			TargetGroupArn: aws.String(targetGroupARN),
+		})
+		if err != nil {
+			return "", err
+		}
+
+		healthy := 0
+		var states []string
+		for _, target := range health.TargetHealthDescriptions {
+			state := aws.StringValue(target.TargetHealth.State)
+			states = append(states, fmt.Sprintf("%s:%s", aws.StringValue(target.Target.Id), state))
+			if state == elbv2.TargetHealthStateEnumHealthy {
+				healthy++
+			}
+		}
+		if healthy == 0 || healthy != len(health.TargetHealthDescriptions) {
+			return "", fmt.Errorf("targets not healthy yet: %v", states)
+		}
+		return fmt.Sprintf("%d healthy targets", healthy), nil
+	})
+	return err
+}
+
+// waitForDeploymentCompleted polls the service until it has a single COMPLETED deployment with all its tasks running
+func waitForDeploymentCompleted(t *testing.T, region, clusterName, serviceName string) (*ecs.Deployment, error) {
+	ecsClient := newECSClient(t, region)
+
+	var deployment *ecs.Deployment
+	_, err := retry.DoWithRetryE(t, fmt.Sprintf("Waiting for deployment of %s to complete", serviceName), 40, 15*time.Second, func() (string, error) {
+		services, err := ecsClient.DescribeServices(&ecs.DescribeServicesInput{
+			Cluster:  aws.String(clusterName),
+			Services: []*string{aws.String(serviceName)},
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(services.Services) != 1 {
+			return "", fmt.Errorf("service %s not found", serviceName)
+		}
+
+		deployments := services.Services[0].Deployments
+		if len(deployments) != 1 {
+			return "", fmt.Errorf("%d deployments in progress", len(deployments))
+		}
+		primary := deployments[0]
+		if aws.StringValue(primary.RolloutState) != ecs.DeploymentRolloutStateCompleted || aws.Int64Value(primary.RunningCount) != aws.Int64Value(primary.DesiredCount) {
+			return "", fmt.Errorf("deployment %s is %s with %d/%d tasks running", aws.StringValue(primary.Id), aws.StringValue(primary.RolloutState), aws.Int64Value(primary.RunningCount), aws.Int64Value(primary.DesiredCount))
+		}
+		deployment = primary
+		return aws.StringValue(primary.Id), nil
+	})
+	return deployment, err
+}
+
+// waitForRollback polls the service until ECS rolls back to originalTaskDefinitionARN and the rollback completes
+// It returns the service event reporting the failed deployment (e.g. "deployment failed: alarm detected")
+func waitForRollback(t *testing.T, region, clusterName, serviceName, originalTaskDefinitionARN string, since time.Time) (string, error) {
+	ecsClient := newECSClient(t, region)
+
+	return retry.DoWithRetryE(t, fmt.Sprintf("Waiting for %s to roll back", serviceName), 60, 15*time.Second, func() (string, error) {
+		services, err := ecsClient.DescribeServices(&ecs.DescribeServicesInput{
+			Cluster:  aws.String(clusterName),
+			Services: []*string{aws.String(serviceName)},
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(services.Services) != 1 {
+			return "", fmt.Errorf("service %s not found", serviceName)
+		}
+		service := services.Services[0]
+
+		failure := ""
+		for _, event := range service.Events {
+			if aws.TimeValue(event.CreatedAt).After(since) && strings.Contains(aws.StringValue(event.Message), "deployment failed") {
+				failure = aws.StringValue(event.Message)
+				break
+			}
+		}
+		if failure == "" {
+			return "", fmt.Errorf("no failed deployment reported yet")
+		}
+
+		if len(service.Deployments) != 1 {
+			return "", fmt.Errorf("rollback in progress (%d deployments)", len(service.Deployments))
+		}
+		primary := service.Deployments[0]
+		if aws.StringValue(primary.TaskDefinition) != originalTaskDefinitionARN {
+			return "", fmt.Errorf("primary deployment uses %s, expected %s", aws.StringValue(primary.TaskDefinition), originalTaskDefinitionARN)
+		}
+		if aws.StringValue(primary.RolloutState) != ecs.DeploymentRolloutStateCompleted {
+			return "", fmt.Errorf("rollback deployment is %s", aws.StringValue(primary.RolloutState))
+		}
+		return failure, nil
+	})
+}
+
+// waitForRolloutState polls the service until the deployment of taskDefinitionARN reaches rolloutState
+// Failed deployments only stay listed while ECS rolls back, so the service is polled every 10 seconds
+func waitForRolloutState(t *testing.T, region, clusterName, serviceName, taskDefinitionARN, rolloutState string) (*ecs.Deployment, error) {
+	ecsClient := newECSClient(t, region)
+
+	var deployment *ecs.Deployment
+	_, err := retry.DoWithRetryE(t, fmt.Sprintf("Waiting for deployment of %s to be %s", taskDefinitionARN, rolloutState), 150, 10*time.Second, func() (string, error) {
+		services, err := ecsClient.DescribeServices(&ecs.DescribeServicesInput{
+			Cluster:  aws.String(clusterName),
+			Services: []*string{aws.String(serviceName)},
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(services.Services) != 1 {
+			return "", fmt.Errorf("service %s not found", serviceName)
+		}
+
+		for _, candidate := range services.Services[0].Deployments {
+			if aws.StringValue(candidate.TaskDefinition) != taskDefinitionARN {
+				continue
+			}
+			if aws.StringValue(candidate.RolloutState) == rolloutState {
+				deployment = candidate
+				return aws.StringValue(candidate.RolloutStateReason), nil
+			}
+			return "", fmt.Errorf("deployment %s is %s (%d failed tasks)", aws.StringValue(candidate.Id), aws.StringValue(candidate.RolloutState), aws.Int64Value(candidate.FailedTasks))
+		}
+		return "", fmt.Errorf("no deployment found for %s", taskDefinitionARN)
+	})
+	return deployment, err
+}
+
+// migrationStartedBy matches the startedBy value the module uses for migration tasks (migration.tf)
+const migrationStartedBy = "terraform-migration"
+
+// getMigrationTask returns the stopped migration task that ran taskDefinitionARN
+// ListTasks can't combine startedBy with other filters, so tasks are listed by family and filtered here
+func getMigrationTask(t *testing.T, region, clusterName, taskDefinitionARN string) (*ecs.Task, error) {
+	ecsClient := newECSClient(t, region)
+	family := taskDefinitionARN[strings.LastIndex(taskDefinitionARN, "/")+1 : strings.LastIndex(taskDefinitionARN, ":")]
+
+	var migrationTask *ecs.Task
+	_, err := retry.DoWithRetryE(t, fmt.Sprintf("Looking for the migration task of %s", taskDefinitionARN), 12, 10*time.Second, func() (string, error) {
+		taskARNs, err := ecsClient.ListTasks(&ecs.ListTasksInput{
+			Cluster:       aws.String(clusterName),
+			Family:        aws.String(family),
+			DesiredStatus: aws.String(ecs.DesiredStatusStopped),
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(taskARNs.TaskArns) == 0 {
+			return "", fmt.Errorf("no stopped tasks in family %s", family)
+		}
+
+		tasks, err := ecsClient.DescribeTasks(&ecs.DescribeTasksInput{
+			Cluster: aws.String(clusterName),
+			Tasks:   taskARNs.TaskArns,
+		})
+		if err != nil {
+			return "", err
+		}
+		for _, task := range tasks.Tasks {
+			if aws.StringValue(task.StartedBy) == migrationStartedBy && aws.StringValue(task.TaskDefinitionArn) == taskDefinitionARN && aws.StringValue(task.LastStatus) == ecs.DesiredStatusStopped {
+				migrationTask = task
+				return aws.StringValue(task.TaskArn), nil
+			}
+		}
+		return "", fmt.Errorf("no stopped migration task for %s", taskDefinitionARN)
+	})
+	return migrationTask, err
+}
+
+// getSchedule returns an EventBridge Scheduler schedule from the default group
+func getSchedule(t *testing.T, region, name string) (*scheduler.GetScheduleOutput, error) {
+	return newSchedulerClient(t, region).GetSchedule(&scheduler.GetScheduleInput{
+		Name:      aws.String(name),
+		GroupName: aws.String("default"),
+	})
+}
+
 // getRandomName generates a unique name for test resources
 func getRandomName(prefix string) string {
 	rand.Seed(time.Now().UnixNano())
@@ -575,14 +1306,14 @@ func sanitizeName(name string) string {
 
 // getAWSAccountID gets the AWS account ID from the current AWS credentials
 func getAWSAccountID(t *testing.T, region string) string {
-	// Use Terratest AWS module to get caller identity
-	accountID := terratestaws.GetAccountId(t)
-	return accountID
+	identity, err := sts.New(newAWSSession(t, region)).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	require.NoError(t, err, "Failed to get caller identity")
+	return aws.StringValue(identity.Account)
 }
 
 // checkDynamoDBTableExists checks if a DynamoDB table exists
 func checkDynamoDBTableExists(t *testing.T, region, tableName string) bool {
-	dynamoClient := terratestaws.NewDynamoDBClient(t, region)
+	dynamoClient := newDynamoDBClient(t, region)
 
 	// Try to describe the table
 	_, err := dynamoClient.DescribeTable(&dynamodb.DescribeTableInput{
@@ -594,7 +1325,7 @@ func checkDynamoDBTableExists(t *testing.T, region, tableName string) bool {
 
 // checkS3BucketExists checks if an S3 bucket exists
 func checkS3BucketExists(t *testing.T, region, bucketName string) bool {
-	s3Client := terratestaws.NewS3Client(t, region)
+	s3Client := newS3Client(t, region)
 
 	// Try to head the bucket (lightweight operation to check existence)
 	_, err := s3Client.HeadBucket(&s3.HeadBucketInput{
@@ -1,13 +1,15 @@
 package test
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/url"
 	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/iam"
-	terratestaws "github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/files"
 	"github.com/gruntwork-io/terratest/modules/terraform"
 	"github.com/stretchr/testify/require"
 )
@@ -17,7 +19,7 @@ func testIAM(t *testing.T, moduleOptions *terraform.Options, infraOutputs *Infra
 	serviceName := terraform.Output(t, moduleOptions, "service_name")
 	awsRegion := infraOutputs.AWSRegion
 
-	iamClient := terratestaws.NewIamClient(t, awsRegion)
+	iamClient := newIAMClient(t, awsRegion)
 
 	// Extract role name from ARN
 	roleName := executionRoleARN[strings.LastIndex(executionRoleARN, "/")+1:]
@@ -100,3 +102,68 @@ func testIAM(t *testing.T, moduleOptions *terraform.Options, infraOutputs *Infra
 	t.Logf("   Contains APIKey ARN (SSM): ✓")
 	t.Logf("   Contains DatabasePassword ARN (Secrets Manager): ✓")
 }
+
+// testRepositoryCredentialsPlan checks at plan time how repository_credentials_secret_arn and secret_variables
+// shape the execution role secrets policy and the container definition (nothing is applied)
+func testRepositoryCredentialsPlan(t *testing.T, infraOutputs *InfrastructureOutputs, testName string) {
+	scenarioName := fmt.Sprintf("%s-rcp", testName)
+
+	moduleDir, err := files.CopyTerraformFolderToTemp("..", scenarioName)
+	require.NoError(t, err)
+	moduleOptions := setupScenarioOptions(t, moduleDir, infraOutputs, scenarioName)
+	// The container definition must be known at plan time
+	moduleOptions.Vars["resolve_image_digest"] = false
+	moduleOptions.Vars["secret_variables"] = []map[string]interface{}{}
+	_, err = terraform.InitE(t, moduleOptions)
+	require.NoError(t, err)
+
+	planWith := func(repositoryCredentialsSecretARN string) *terraform.PlanStruct {
+		planOptions, err := moduleOptions.Clone()
+		require.NoError(t, err)
+		if repositoryCredentialsSecretARN != "" {
+			planOptions.Vars["repository_credentials_secret_arn"] = repositoryCredentialsSecretARN
+		}
+		plan, err := planModule(t, planOptions)
+		require.NoError(t, err, "Plan should succeed")
+		return plan
+	}
+
+	containerDefinition := func(plan *terraform.PlanStruct) map[string]interface{} {
+		change, ok := plan.ResourceChangesMap["aws_ecs_task_definition.webapp"]
+		require.True(t, ok, "The plan should include the task definition")
+		after := change.Change.After.(map[string]interface{})
+		var containerDefinitions []map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(after["container_definitions"].(string)), &containerDefinitions))
+		require.Len(t, containerDefinitions, 1)
+		return containerDefinitions[0]
+	}
+
+	// Without secrets nor registry credentials there is nothing for the execution role to read
+	t.Logf("📝 Planning without secret_variables nor repository_credentials_secret_arn...")
+	plan := planWith("")
+	_, ok := plan.ResourceChangesMap["aws_iam_role_policy.execution_secrets_policy[0]"]
+	require.False(t, ok, "No secrets policy should be created without secrets")
+	require.Nil(t, containerDefinition(plan)["repositoryCredentials"], "No repositoryCredentials without registry credentials")
+
+	// Registry credentials alone: a single Secrets Manager statement for the credentials secret
+	t.Logf("📝 Planning with repository_credentials_secret_arn...")
+	plan = planWith(infraOutputs.DatabasePasswordARN)
+	require.Equal(t,
+		map[string]interface{}{"credentialsParameter": infraOutputs.DatabasePasswordARN},
+		containerDefinition(plan)["repositoryCredentials"])
+
+	change, ok := plan.ResourceChangesMap["aws_iam_role_policy.execution_secrets_policy[0]"]
+	require.True(t, ok, "The secrets policy should be created for the registry credentials")
+	var policy struct {
+		Statement []struct {
+			Action   []string
+			Resource []string
+		}
+	}
+	require.NoError(t, json.Unmarshal([]byte(change.Change.After.(map[string]interface{})["policy"].(string)), &policy))
+	require.Len(t, policy.Statement, 1, "Only the Secrets Manager statement should be created")
+	require.Equal(t, []string{"secretsmanager:GetSecretValue"}, policy.Statement[0].Action)
+	require.Equal(t, []string{infraOutputs.DatabasePasswordARN}, policy.Statement[0].Resource)
+
+	t.Logf("✅ Repository credentials and secrets policy planned as expected")
+}
@@ -0,0 +1,120 @@
+package test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func testMigrationTask(t *testing.T, infraOutputs *InfrastructureOutputs, testName string) {
+	scenarioName := fmt.Sprintf("%s-mig", testName)
+	region := infraOutputs.AWSRegion
+
+	moduleOptions, err := deployModuleScenario(t, infraOutputs, scenarioName, func(vars map[string]interface{}) {
+		vars["migration"] = map[string]interface{}{
+			"command":         []string{"sh", "-c", "echo running migrations && sleep 5 && echo migrations done"},
+			"timeout_seconds": 600,
+		}
+	})
+	require.NoError(t, err, "Scenario with a successful migration should apply")
+
+	clusterName := terraform.Output(t, moduleOptions, "cluster_name")
+	serviceName := terraform.Output(t, moduleOptions, "service_name")
+	taskDefinitionARN := terraform.Output(t, moduleOptions, "ecs_task_definition_arn")
+
+	t.Logf("🗄️  Verifying the migration task of %s...", taskDefinitionARN)
+	migrationTask, err := getMigrationTask(t, region, clusterName, taskDefinitionARN)
+	require.NoError(t, err, "The migration task should have run the service task definition")
+	requireMigrationExitCode(t, migrationTask, serviceName, 0)
+
+	// The service is only created once the migration has stopped
+	ecsService := getECSService(t, region, clusterName, serviceName)
+	t.Logf("   Migration stopped at %s, service created at %s", aws.TimeValue(migrationTask.StoppedAt), aws.TimeValue(ecsService.CreatedAt))
+	require.True(t, aws.TimeValue(migrationTask.StoppedAt).Before(aws.TimeValue(ecsService.CreatedAt)), "The migration should finish before the service is created")
+
+	// A new task definition revision runs the migration again before its deployment starts
+	t.Logf("🔄 Rolling out a new task definition revision...")
+	moduleOptions.Vars["environment_variables"] = []map[string]interface{}{
+		{
+			"name":  "RELEASE",
+			"value": "2",
+		},
+	}
+	_, err = terraform.ApplyE(t, moduleOptions)
+	require.NoError(t, err, "New revision with a successful migration should apply")
+	newTaskDefinitionARN := terraform.Output(t, moduleOptions, "ecs_task_definition_arn")
+	require.NotEqual(t, taskDefinitionARN, newTaskDefinitionARN)
+
+	migrationTask, err = getMigrationTask(t, region, clusterName, newTaskDefinitionARN)
+	require.NoError(t, err, "The migration should run again for the new revision")
+	requireMigrationExitCode(t, migrationTask, serviceName, 0)
+
+	deployment, err := waitForDeploymentCompleted(t, region, clusterName, serviceName)
+	require.NoError(t, err, "Deployment of the new revision should complete")
+	require.Equal(t, newTaskDefinitionARN, aws.StringValue(deployment.TaskDefinition))
+	t.Logf("   Migration stopped at %s, deployment created at %s", aws.TimeValue(migrationTask.StoppedAt), aws.TimeValue(deployment.CreatedAt))
+	require.True(t, aws.TimeValue(migrationTask.StoppedAt).Before(aws.TimeValue(deployment.CreatedAt)), "The migration should finish before the new revision is deployed")
+
+	t.Logf("✅ Migration task tests passed!")
+}
+
+func testFailedMigrationTask(t *testing.T, infraOutputs *InfrastructureOutputs, testName string) {
+	scenarioName := fmt.Sprintf("%s-migf", testName)
+	region := infraOutputs.AWSRegion
+
+	moduleOptions, err := deployModuleScenario(t, infraOutputs, scenarioName, func(vars map[string]interface{}) {
+		vars["migration"] = map[string]interface{}{
+			"command":         []string{"sh", "-c", "echo migration failed >&2; exit 3"},
+			"timeout_seconds": 600,
+		}
+	})
+	require.Error(t, err, "Apply should fail when the migration task exits with a non-zero code")
+
+	clusterName := infraOutputs.ClusterName
+	serviceName := moduleOptions.Vars["service_name"].(string)
+
+	// The task definition (family = service name) is registered before the migration runs
+	ecsClient := newECSClient(t, region)
+	taskDefinitions, err := ecsClient.ListTaskDefinitions(&ecs.ListTaskDefinitionsInput{
+		FamilyPrefix: aws.String(serviceName),
+		Sort:         aws.String(ecs.SortOrderDesc),
+		MaxResults:   aws.Int64(1),
+	})
+	require.NoError(t, err)
+	require.Len(t, taskDefinitions.TaskDefinitionArns, 1, "The task definition should be registered before the migration")
+	taskDefinitionARN := aws.StringValue(taskDefinitions.TaskDefinitionArns[0])
+
+	t.Logf("🗄️  Verifying the failed migration task of %s...", taskDefinitionARN)
+	migrationTask, err := getMigrationTask(t, region, clusterName, taskDefinitionARN)
+	require.NoError(t, err, "The migration task should have run before the apply failed")
+	requireMigrationExitCode(t, migrationTask, serviceName, 3)
+
+	// The failed migration stops the apply before the service is created
+	services, err := ecsClient.DescribeServices(&ecs.DescribeServicesInput{
+		Cluster:  aws.String(clusterName),
+		Services: []*string{aws.String(serviceName)},
+	})
+	require.NoError(t, err)
+	for _, service := range services.Services {
+		require.NotEqual(t, "ACTIVE", aws.StringValue(service.Status), "Service %s should not be created after a failed migration", serviceName)
+	}
+
+	t.Logf("✅ Failed migration task tests passed!")
+}
+
+// requireMigrationExitCode checks the exit code of the service container in a stopped migration task
+func requireMigrationExitCode(t *testing.T, task *ecs.Task, containerName string, exitCode int64) {
+	t.Logf("   Migration task: %s (%s)", aws.StringValue(task.TaskArn), aws.StringValue(task.StoppedReason))
+	for _, container := range task.Containers {
+		if aws.StringValue(container.Name) == containerName {
+			require.NotNil(t, container.ExitCode, "Container %s should have an exit code", containerName)
+			require.Equal(t, exitCode, aws.Int64Value(container.ExitCode))
+			return
+		}
+	}
+	require.Failf(t, "Container not found", "Migration task %s has no container %s", aws.StringValue(task.TaskArn), containerName)
+}
@@ -0,0 +1,192 @@
+package test
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// TestResourceNames applies the naming module on its own (no provider, no AWS credentials needed)
+// and checks names at and around each AWS length limit
+func TestResourceNames(t *testing.T) {
+	moduleDir, err := files.CopyTerraformFolderToTemp("../modules/naming", "naming")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		base          string
+		resource      map[string]interface{}
+		expectedError string
+	}{
+		{
+			name:     "Short target group name is unchanged",
+			base:     "api",
+			resource: map[string]interface{}{"suffix": "-tg", "max_length": 32, "hyphens_only": true},
+		},
+		{
+			name:     "Target group name exactly at the limit is unchanged",
+			base:     strings.Repeat("a", 29),
+			resource: map[string]interface{}{"suffix": "-tg", "max_length": 32, "hyphens_only": true},
+		},
+		{
+			name:     "Target group name one over the limit is hashed",
+			base:     strings.Repeat("a", 30),
+			resource: map[string]interface{}{"suffix": "-tg", "max_length": 32, "hyphens_only": true},
+		},
+		{
+			name:     "Additional port target group with the longest port name",
+			base:     "payments-service",
+			resource: map[string]interface{}{"suffix": "-abcdefghijklmnop-tg", "max_length": 32, "hyphens_only": true},
+		},
+		{
+			name:     "Separators at the truncation point are trimmed",
+			base:     "payments-api-public-gateway",
+			resource: map[string]interface{}{"suffix": "-tg", "max_length": 32, "hyphens_only": true},
+		},
+		{
+			name:     "Underscores at the truncation point are trimmed",
+			base:     strings.Repeat("a", 39) + "__legacy",
+			resource: map[string]interface{}{"suffix": "-execution-role", "max_length": 64},
+		},
+		{
+			name:     "Underscores are replaced in target group names",
+			base:     "legacy_api",
+			resource: map[string]interface{}{"suffix": "-tg", "max_length": 32, "hyphens_only": true},
+		},
+		{
+			name:     "Underscores are kept in IAM role names",
+			base:     "legacy_api",
+			resource: map[string]interface{}{"suffix": "-execution-role", "max_length": 64},
+		},
+		{
+			name:     "Maximum service name for the execution role",
+			base:     strings.Repeat("s", 255),
+			resource: map[string]interface{}{"suffix": "-execution-role", "max_length": 64},
+		},
+		{
+			name:     "Maximum service name for a prefixed scaling policy",
+			base:     strings.Repeat("s", 255),
+			resource: map[string]interface{}{"prefix": "alb-request-count-scaling-policy-", "max_length": 256},
+		},
+		{
+			name:          "Suffix leaving no room for the hash is rejected at plan time",
+			base:          "api",
+			resource:      map[string]interface{}{"suffix": "-this-suffix-is-far-too-long-tg", "max_length": 32},
+			expectedError: "must leave room",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			moduleOptions := &terraform.Options{
+				TerraformDir:    moduleDir,
+				TerraformBinary: "terraform",
+				NoColor:         true,
+				Vars: map[string]interface{}{
+					"name":      testCase.base,
+					"resources": map[string]interface{}{"resource": testCase.resource},
+				},
+			}
+
+			_, err := terraform.InitAndApplyE(t, moduleOptions)
+			if testCase.expectedError != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), testCase.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			name := terraform.OutputMap(t, moduleOptions, "names")["resource"]
+			expected := expectedResourceName(testCase.base, testCase.resource)
+			t.Logf("   %s (%d chars)", name, len(name))
+			require.Equal(t, expected, name)
+			require.LessOrEqual(t, len(name), testCase.resource["max_length"].(int))
+		})
+	}
+}
+
+// expectedResourceName mirrors modules/naming: the full name when it fits, otherwise
+// the truncated base name (without trailing separators) plus the first 8 characters of the full name's SHA-1
+func expectedResourceName(base string, resource map[string]interface{}) string {
+	prefix, _ := resource["prefix"].(string)
+	suffix, _ := resource["suffix"].(string)
+	maxLength := resource["max_length"].(int)
+	if hyphensOnly, _ := resource["hyphens_only"].(bool); hyphensOnly {
+		base = strings.ReplaceAll(base, "_", "-")
+	}
+
+	fullName := prefix + base + suffix
+	if len(fullName) <= maxLength {
+		return fullName
+	}
+
+	hash := sha1.Sum([]byte(fullName))
+	return prefix + strings.TrimRight(base[:maxLength-len(prefix)-len(suffix)-9], "-_") + "-" + hex.EncodeToString(hash[:])[:8] + suffix
+}
+
+// testLongServiceNamePlan plans the whole module with a service_name far over the target group and IAM limits,
+// with separators right where the names are truncated (nothing is applied)
+func testLongServiceNamePlan(t *testing.T, infraOutputs *InfrastructureOutputs, testName string) {
+	scenarioName := fmt.Sprintf("%s-lsn", testName)
+
+	moduleDir, err := files.CopyTerraformFolderToTemp("..", scenarioName)
+	require.NoError(t, err)
+	moduleOptions := setupScenarioOptions(t, moduleDir, infraOutputs, scenarioName)
+
+	// Target groups keep 20 characters of the base name ("-tg") and the execution role 40 ("-execution-role")
+	serviceName := strings.Repeat("a", 19) + "-" + strings.Repeat("b", 19) + "_" + scenarioName + "-" + strings.Repeat("c", 100)
+	moduleOptions.Vars["service_name"] = serviceName
+	if infraOutputs.ALBLoadBalancerARN != "" {
+		moduleOptions.Vars["additional_ports"] = []map[string]interface{}{
+			{
+				"name": "adm",
+				"port": 8080,
+				"target_group": map[string]interface{}{
+					"listener_rules": []map[string]interface{}{
+						{
+							"priority":      nextScenarioListenerPriority(),
+							"path_patterns": []string{fmt.Sprintf("/%s-adm/*", scenarioName)},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	// The provider validates name lengths at plan time, so the plan itself fails if a name is too long
+	t.Logf("📝 Planning with a %d character service_name...", len(serviceName))
+	_, err = terraform.InitE(t, moduleOptions)
+	require.NoError(t, err)
+	plan, err := planModule(t, moduleOptions)
+	require.NoError(t, err, "Plan with a long service_name should succeed")
+
+	limits := map[string]int{
+		"aws_lb_target_group": 32,
+		"aws_iam_role":        64,
+		"aws_iam_role_policy": 128,
+	}
+	found := map[string]int{}
+	for address, change := range plan.ResourceChangesMap {
+		limit, ok := limits[change.Type]
+		if !ok {
+			continue
+		}
+		name := change.Change.After.(map[string]interface{})["name"].(string)
+		t.Logf("   %s: %s (%d chars)", address, name, len(name))
+		require.LessOrEqual(t, len(name), limit, "%s name should fit the AWS limit", address)
+		require.NotRegexp(t, `[-_]{2}`, name, "%s name should not have doubled separators", address)
+		found[change.Type]++
+	}
+	require.NotZero(t, found["aws_iam_role"], "The plan should include the IAM roles")
+	if infraOutputs.ALBLoadBalancerARN != "" {
+		require.Equal(t, 2, found["aws_lb_target_group"], "The plan should include the main and additional port target groups")
+	}
+
+	t.Logf("✅ Every name fits the AWS limits")
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	http_helper "github.com/gruntwork-io/terratest/modules/http-helper"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func testNLBMode(t *testing.T, infraOutputs *InfrastructureOutputs, testName string) {
+	if infraOutputs.NLBLoadBalancerARN == "" {
+		t.Logf("⏭️  Skipping NLB mode test (NLB not configured)")
+		return
+	}
+
+	scenarioName := fmt.Sprintf("%s-n", testName)
+	listenerPort := 8080
+
+	moduleOptions, err := deployModuleScenario(t, infraOutputs, scenarioName, func(vars map[string]interface{}) {
+		// Serve only through the NLB so the security group rules come from the nlb block alone
+		for _, name := range []string{"alb_load_balancer_arn", "alb_listener_arn", "alb_security_group_id", "listener_rules"} {
+			delete(vars, name)
+		}
+		vars["nlb"] = map[string]interface{}{
+			"load_balancer_arn": infraOutputs.NLBLoadBalancerARN,
+			"security_group_id": infraOutputs.NLBSecurityGroupID,
+			"protocol":          "TCP",
+			"listener": map[string]interface{}{
+				"port": listenerPort,
+			},
+			"health_check": map[string]interface{}{
+				"protocol":            "TCP",
+				"interval":            10,
+				"healthy_threshold":   2,
+				"unhealthy_threshold": 2,
+			},
+		}
+	})
+	require.NoError(t, err, "Scenario with nlb should apply")
+
+	clusterName := terraform.Output(t, moduleOptions, "cluster_name")
+	serviceName := terraform.Output(t, moduleOptions, "service_name")
+	targetGroupARN := terraform.Output(t, moduleOptions, "nlb_target_group_arn")
+	listenerARN := terraform.Output(t, moduleOptions, "nlb_listener_arn")
+	require.NotEmpty(t, targetGroupARN, "nlb_target_group_arn should be set when nlb is configured")
+	require.NotEmpty(t, listenerARN, "nlb_listener_arn should be set when nlb.listener is configured")
+
+	// Verify the target group uses TCP with TCP health checks
+	t.Logf("🎯 Verifying NLB target group...")
+	elbClient := newELBv2Client(t, infraOutputs.AWSRegion)
+	targetGroups, err := elbClient.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{
+		TargetGroupArns: []*string{aws.String(targetGroupARN)},
+	})
+	require.NoError(t, err)
+	require.Len(t, targetGroups.TargetGroups, 1)
+	targetGroup := targetGroups.TargetGroups[0]
+	t.Logf("   Protocol: %s", aws.StringValue(targetGroup.Protocol))
+	t.Logf("   Health Check Protocol: %s", aws.StringValue(targetGroup.HealthCheckProtocol))
+	require.Equal(t, "TCP", aws.StringValue(targetGroup.Protocol))
+	require.Equal(t, "TCP", aws.StringValue(targetGroup.HealthCheckProtocol))
+	require.Equal(t, "ip", aws.StringValue(targetGroup.TargetType))
+	require.Equal(t, int64(80), aws.Int64Value(targetGroup.Port))
+	require.Contains(t, aws.StringValueSlice(targetGroup.LoadBalancerArns), infraOutputs.NLBLoadBalancerARN)
+
+	// Verify the listener forwards to the target group
+	t.Logf("👂 Verifying NLB listener...")
+	listeners, err := elbClient.DescribeListeners(&elbv2.DescribeListenersInput{
+		ListenerArns: []*string{aws.String(listenerARN)},
+	})
+	require.NoError(t, err)
+	require.Len(t, listeners.Listeners, 1)
+	listener := listeners.Listeners[0]
+	require.Equal(t, int64(listenerPort), aws.Int64Value(listener.Port))
+	require.Equal(t, "TCP", aws.StringValue(listener.Protocol))
+	require.Len(t, listener.DefaultActions, 1)
+	require.Equal(t, targetGroupARN, aws.StringValue(listener.DefaultActions[0].TargetGroupArn))
+
+	// Verify the service registers its tasks in the NLB target group only
+	ecsClient := newECSClient(t, infraOutputs.AWSRegion)
+	service, err := ecsClient.DescribeServices(&ecs.DescribeServicesInput{
+		Cluster:  aws.String(clusterName),
+		Services: []*string{aws.String(serviceName)},
+	})
+	require.NoError(t, err)
+	require.Len(t, service.Services, 1)
+	require.Len(t, service.Services[0].LoadBalancers, 1)
+	require.Equal(t, targetGroupARN, aws.StringValue(service.Services[0].LoadBalancers[0].TargetGroupArn))
+	require.Equal(t, int64(80), aws.Int64Value(service.Services[0].LoadBalancers[0].ContainerPort))
+
+	// Only the NLB security group (and the VPC) may reach the container port
+	testSecurityGroup(t, moduleOptions, infraOutputs)
+
+	if isLocalStack() {
+		t.Logf("⏭️  Skipping NLB health checks and traffic on LocalStack: the emulator does not health check targets or route traffic through NLB listeners")
+		t.Logf("✅ NLB mode tests passed!")
+		return
+	}
+
+	t.Logf("💚 Waiting for NLB targets to become healthy...")
+	err = waitForHealthyTargets(t, infraOutputs.AWSRegion, targetGroupARN)
+	require.NoError(t, err, "NLB targets should pass TCP health checks")
+
+	// End to end: nginx answers through the NLB listener
+	url := fmt.Sprintf("http://%s:%d/", infraOutputs.NLBDNSName, listenerPort)
+	t.Logf("🌐 Requesting %s...", url)
+	err = http_helper.HttpGetWithRetryWithCustomValidationE(t, url, nil, 30, 10*time.Second, func(status int, body string) bool {
+		return status == 200 && strings.Contains(body, "nginx")
+	})
+	require.NoError(t, err, "nginx should answer through the NLB listener")
+
+	t.Logf("✅ NLB mode tests passed!")
+}
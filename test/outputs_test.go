@@ -20,6 +20,13 @@ func testOutputs(t *testing.T, moduleOptions *terraform.Options, infraOutputs *I
 		require.Empty(t, albTargetGroupARN, "Target Group ARN should be empty when ALB is not configured")
 	}
 
+	albTargetGroupARNs := terraform.OutputMap(t, moduleOptions, "alb_target_group_arns")
+	if infraOutputs.ALBLoadBalancerARN != "" {
+		require.Equal(t, albTargetGroupARN, albTargetGroupARNs["main"], "alb_target_group_arns['main'] should match alb_target_group_arn")
+	} else {
+		require.Empty(t, albTargetGroupARNs, "Target Group ARNs map should be empty when ALB is not configured")
+	}
+
 	ecsServiceName := terraform.Output(t, moduleOptions, "ecs_service_name")
 	require.NotEmpty(t, ecsServiceName)
 
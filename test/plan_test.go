@@ -0,0 +1,69 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestDiffPlanValues checks the drift report used by checkEmptyPlan (no AWS credentials needed)
+func TestDiffPlanValues(t *testing.T) {
+	testCases := []struct {
+		name         string
+		before       interface{}
+		after        interface{}
+		afterUnknown interface{}
+		expected     []string
+	}{
+		{
+			name:     "Identical values have no drift",
+			before:   map[string]interface{}{"desired_count": 1.0, "tags": map[string]interface{}{"Env": "test"}},
+			after:    map[string]interface{}{"desired_count": 1.0, "tags": map[string]interface{}{"Env": "test"}},
+			expected: nil,
+		},
+		{
+			name:     "Nested attributes are reported by path",
+			before:   map[string]interface{}{"desired_count": 2.0, "network_configuration": []interface{}{map[string]interface{}{"assign_public_ip": false}}},
+			after:    map[string]interface{}{"desired_count": 1.0, "network_configuration": []interface{}{map[string]interface{}{"assign_public_ip": true}}},
+			expected: []string{"desired_count: 2 -> 1", "network_configuration[0].assign_public_ip: false -> true"},
+		},
+		{
+			name:         "Unknown values are reported as known after apply",
+			before:       map[string]interface{}{"arn": "arn:aws:ecs:us-west-2:123456789012:task-definition/app:1"},
+			after:        map[string]interface{}{"arn": nil},
+			afterUnknown: map[string]interface{}{"arn": true},
+			expected:     []string{`arn: "arn:aws:ecs:us-west-2:123456789012:task-definition/app:1" -> (known after apply)`},
+		},
+		{
+			name:     "JSON strings are diffed field by field",
+			before:   map[string]interface{}{"container_definitions": `[{"name":"app","cpu":0,"essential":true}]`},
+			after:    map[string]interface{}{"container_definitions": `[{"essential":true,"name":"app","cpu":256}]`},
+			expected: []string{"container_definitions[0].cpu: 0 -> 256"},
+		},
+		{
+			name:     "JSON key ordering alone is reported as formatting",
+			before:   map[string]interface{}{"container_definitions": `[{"name":"app","essential":true}]`},
+			after:    map[string]interface{}{"container_definitions": `[{"essential":true,"name":"app"}]`},
+			expected: []string{"container_definitions: JSON formatting only"},
+		},
+		{
+			name:     "Lists of different length are reported as a whole",
+			before:   map[string]interface{}{"subnets": []interface{}{"subnet-a"}},
+			after:    map[string]interface{}{"subnets": []interface{}{"subnet-a", "subnet-b"}},
+			expected: []string{`subnets: ["subnet-a"] -> ["subnet-a","subnet-b"]`},
+		},
+		{
+			name:     "Created resources are reported at the top level",
+			before:   nil,
+			after:    map[string]interface{}{"name": "app"},
+			expected: []string{`(resource): null -> {"name":"app"}`},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, diffPlanValues("", tc.before, tc.after, tc.afterUnknown))
+		})
+	}
+}
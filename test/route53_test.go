@@ -0,0 +1,86 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func testRoute53Aliases(t *testing.T, infraOutputs *InfrastructureOutputs, testName string) {
+	if infraOutputs.ALBLoadBalancerARN == "" || infraOutputs.PrivateZoneID == "" {
+		t.Logf("⏭️  Skipping Route 53 aliases test (ALB or private hosted zone not configured)")
+		return
+	}
+
+	scenarioName := fmt.Sprintf("%s-dns", testName)
+	hostName := fmt.Sprintf("%s.%s", scenarioName, strings.TrimSuffix(infraOutputs.PrivateZoneName, "."))
+
+	moduleOptions, err := deployModuleScenario(t, infraOutputs, scenarioName, func(vars map[string]interface{}) {
+		vars["listener_rules"] = []map[string]interface{}{
+			{
+				"priority":     nextScenarioListenerPriority(),
+				"host_headers": []string{hostName},
+			},
+		}
+		vars["route53"] = map[string]interface{}{
+			"zone_id": infraOutputs.PrivateZoneID,
+		}
+	})
+	require.NoError(t, err, "Scenario with route53 should apply")
+
+	recordNames := terraform.OutputList(t, moduleOptions, "route53_record_names")
+	t.Logf("🌐 Route 53 record names: %v", recordNames)
+	require.Equal(t, []string{hostName}, recordNames)
+
+	// The alias target must be the ALB the listener rules belong to
+	elbClient := newELBv2Client(t, infraOutputs.AWSRegion)
+	loadBalancers, err := elbClient.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{
+		LoadBalancerArns: []*string{aws.String(infraOutputs.ALBLoadBalancerARN)},
+	})
+	require.NoError(t, err)
+	require.Len(t, loadBalancers.LoadBalancers, 1)
+	loadBalancer := loadBalancers.LoadBalancers[0]
+	albDNSName := strings.ToLower(aws.StringValue(loadBalancer.DNSName))
+
+	// AAAA only for ALBs with IPv6 (the fixture ALB is ipv4)
+	expectedTypes := []string{route53.RRTypeA}
+	if strings.HasPrefix(aws.StringValue(loadBalancer.IpAddressType), "dualstack") {
+		expectedTypes = append(expectedTypes, route53.RRTypeAaaa)
+	}
+	t.Logf("   ALB %s (%s), expected records: %v", albDNSName, aws.StringValue(loadBalancer.IpAddressType), expectedTypes)
+
+	t.Logf("🔍 Reading the %s records from the private zone...", hostName)
+	route53Client := newRoute53Client(t, infraOutputs.AWSRegion)
+	recordSets, err := route53Client.ListResourceRecordSets(&route53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(infraOutputs.PrivateZoneID),
+		StartRecordName: aws.String(hostName),
+		MaxItems:        aws.String("10"),
+	})
+	require.NoError(t, err)
+
+	var recordTypes []string
+	for _, recordSet := range recordSets.ResourceRecordSets {
+		if strings.TrimSuffix(aws.StringValue(recordSet.Name), ".") != hostName {
+			continue
+		}
+		recordType := aws.StringValue(recordSet.Type)
+		recordTypes = append(recordTypes, recordType)
+
+		aliasTarget := recordSet.AliasTarget
+		require.NotNil(t, aliasTarget, "Record %s %s should be an alias", hostName, recordType)
+		aliasDNSName := strings.ToLower(strings.TrimSuffix(aws.StringValue(aliasTarget.DNSName), "."))
+		t.Logf("   %s %s -> %s (zone %s)", hostName, recordType, aliasDNSName, aws.StringValue(aliasTarget.HostedZoneId))
+		require.True(t, strings.HasSuffix(aliasDNSName, albDNSName), "%s alias should point at the ALB (%s)", recordType, albDNSName)
+		require.Equal(t, aws.StringValue(loadBalancer.CanonicalHostedZoneId), aws.StringValue(aliasTarget.HostedZoneId), "%s alias should use the ALB hosted zone", recordType)
+		require.True(t, aws.BoolValue(aliasTarget.EvaluateTargetHealth), "%s alias should evaluate the ALB health", recordType)
+	}
+	require.ElementsMatch(t, expectedTypes, recordTypes, "Alias records for %s", hostName)
+
+	t.Logf("✅ Route 53 aliases tests passed!")
+}
@@ -0,0 +1,78 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func testScheduledTasks(t *testing.T, infraOutputs *InfrastructureOutputs, testName string) {
+	scenarioName := fmt.Sprintf("%s-sch", testName)
+	reportCommand := []string{"sh", "-c", "echo nightly-report"}
+
+	moduleOptions, err := deployModuleScenario(t, infraOutputs, scenarioName, func(vars map[string]interface{}) {
+		vars["scheduled_tasks"] = []map[string]interface{}{
+			{
+				"name":                "report",
+				"schedule_expression": "rate(1 day)",
+				"command":             reportCommand,
+				"task_count":          2,
+			},
+			{
+				"name":                "cleanup",
+				"schedule_expression": "cron(0 3 * * ? *)",
+				"enabled":             false,
+			},
+		}
+	})
+	require.NoError(t, err, "Scenario with scheduled_tasks should apply")
+
+	clusterName := terraform.Output(t, moduleOptions, "cluster_name")
+	taskDefinitionARN := terraform.Output(t, moduleOptions, "ecs_task_definition_arn")
+	securityGroupID := terraform.Output(t, moduleOptions, "security_group_id")
+	schedulerRoleARN := terraform.Output(t, moduleOptions, "scheduler_role_arn")
+	scheduleARNs := terraform.OutputMap(t, moduleOptions, "scheduled_task_arns")
+
+	t.Logf("⏰ Verifying scheduled tasks...")
+	t.Logf("   Schedules: %v", scheduleARNs)
+	require.Len(t, scheduleARNs, 2)
+	require.NotEmpty(t, schedulerRoleARN)
+
+	expectedStates := map[string]string{"report": "ENABLED", "cleanup": "DISABLED"}
+	for name, arn := range scheduleARNs {
+		scheduleName := arn[strings.LastIndex(arn, "/")+1:]
+		schedule, err := getSchedule(t, infraOutputs.AWSRegion, scheduleName)
+		require.NoError(t, err, "Schedule %s should exist", scheduleName)
+
+		ecsParameters := schedule.Target.EcsParameters
+		awsvpcConfiguration := ecsParameters.NetworkConfiguration.AwsvpcConfiguration
+		t.Logf("   %s: %s (%s)", scheduleName, aws.StringValue(schedule.ScheduleExpression), aws.StringValue(schedule.State))
+		t.Logf("      Target: %s", aws.StringValue(schedule.Target.Arn))
+		t.Logf("      Task Definition: %s x%d", aws.StringValue(ecsParameters.TaskDefinitionArn), aws.Int64Value(ecsParameters.TaskCount))
+
+		require.Equal(t, expectedStates[name], aws.StringValue(schedule.State))
+
+		// The target is the service cluster, running the service task definition with its network configuration
+		require.True(t, strings.HasSuffix(aws.StringValue(schedule.Target.Arn), fmt.Sprintf(":cluster/%s", clusterName)), "Schedule %s should target cluster %s", name, clusterName)
+		require.Equal(t, schedulerRoleARN, aws.StringValue(schedule.Target.RoleArn))
+		require.Equal(t, taskDefinitionARN, aws.StringValue(ecsParameters.TaskDefinitionArn))
+		require.Equal(t, "FARGATE", aws.StringValue(ecsParameters.LaunchType))
+		require.ElementsMatch(t, moduleOptions.Vars["subnet_ids"], aws.StringValueSlice(awsvpcConfiguration.Subnets))
+		require.Contains(t, aws.StringValueSlice(awsvpcConfiguration.SecurityGroups), securityGroupID)
+		require.Equal(t, "DISABLED", aws.StringValue(awsvpcConfiguration.AssignPublicIp))
+
+		if name == "report" {
+			require.Equal(t, int64(2), aws.Int64Value(ecsParameters.TaskCount))
+			require.Contains(t, aws.StringValue(schedule.Target.Input), "nightly-report", "The report schedule should override the container command")
+		} else {
+			require.Equal(t, int64(1), aws.Int64Value(ecsParameters.TaskCount))
+			require.Empty(t, aws.StringValue(schedule.Target.Input), "Schedules without command should keep the container command")
+		}
+	}
+
+	t.Logf("✅ Scheduled tasks tests passed!")
+}
@@ -0,0 +1,46 @@
+package test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func testSecretInjection(t *testing.T, infraOutputs *InfrastructureOutputs, testName string) {
+	scenarioName := fmt.Sprintf("%s-sec", testName)
+	region := infraOutputs.AWSRegion
+
+	moduleOptions, err := deployModuleScenario(t, infraOutputs, scenarioName, func(vars map[string]interface{}) {
+		// Log a sha256 of each secret (never the value itself) before starting nginx
+		vars["container_command"] = []string{
+			"sh", "-c",
+			"for name in TEST_SECRET API_KEY DATABASE_PASSWORD; do " +
+				"echo \"SECRET_HASH $name=$(printenv \"$name\" | tr -d '\\n' | sha256sum | cut -d ' ' -f 1)\"; " +
+				"done && exec nginx -g 'daemon off;'",
+		}
+	})
+	require.NoError(t, err, "Scenario with the secret hash command should apply")
+
+	serviceName := terraform.Output(t, moduleOptions, "service_name")
+	logGroupName := terraform.Output(t, moduleOptions, "cloudwatch_log_group_name")
+
+	// Values stored by the fixtures behind the secret_variables set in setupModuleOptions
+	require.Len(t, infraOutputs.SecretValues, 3, "The fixtures should expose the secret values")
+
+	t.Logf("🔐 Verifying the container received the secret values...")
+	for name, value := range infraOutputs.SecretValues {
+		hash := sha256.Sum256([]byte(value))
+		expected := fmt.Sprintf("SECRET_HASH %s=%s", name, hex.EncodeToString(hash[:]))
+
+		message, err := waitForLogEvent(t, region, logGroupName, serviceName+"/", fmt.Sprintf("%q", fmt.Sprintf("SECRET_HASH %s=", name)))
+		require.NoError(t, err, "Container should log the hash of %s", name)
+		t.Logf("   %s", message)
+		require.Contains(t, message, expected, "%s in the container should match the fixture value", name)
+	}
+
+	t.Logf("✅ Secret injection tests passed!")
+}
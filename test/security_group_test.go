@@ -1,12 +1,13 @@
 package test
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
-	terratestaws "github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/gruntwork-io/terratest/modules/terraform"
 	"github.com/stretchr/testify/require"
 )
@@ -14,9 +15,10 @@ import (
 func testSecurityGroup(t *testing.T, moduleOptions *terraform.Options, infraOutputs *InfrastructureOutputs) {
 	securityGroupID := terraform.Output(t, moduleOptions, "security_group_id")
 	serviceName := terraform.Output(t, moduleOptions, "service_name")
+	clusterName := terraform.Output(t, moduleOptions, "cluster_name")
 	region := infraOutputs.AWSRegion
 
-	ec2Client := terratestaws.NewEc2Client(t, region)
+	ec2Client := newEC2Client(t, region)
 
 	// Get Security Group
 	sg, err := ec2Client.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
@@ -32,52 +34,206 @@ func testSecurityGroup(t *testing.T, moduleOptions *terraform.Options, infraOutp
 	require.Equal(t, infraOutputs.VPCID, *securityGroup.VpcId)
 	require.Contains(t, strings.ToLower(*securityGroup.GroupName), strings.ToLower(serviceName))
 
-	// Verify ingress rules
-	ingressRules := securityGroup.IpPermissions
-	require.Greater(t, len(ingressRules), 0)
-
-	// Find rule allowing traffic from ALB security group (only if ALB is configured)
-	foundALBRule := false
-	foundVPCRule := false
-	for _, rule := range ingressRules {
-		if *rule.IpProtocol == "tcp" && len(rule.UserIdGroupPairs) > 0 {
-			for _, pair := range rule.UserIdGroupPairs {
-				if infraOutputs.ALBSecurityGroupID != "" && *pair.GroupId == infraOutputs.ALBSecurityGroupID {
-					foundALBRule = true
-					require.Equal(t, int64(80), *rule.FromPort)
-					require.Equal(t, int64(80), *rule.ToPort)
-				}
+	// Verify the rule set is exactly the one the module vars describe, no more and no less
+	expectedIngress, expectedEgress := expectedSecurityGroupRules(moduleOptions.Vars, infraOutputs)
+	actualIngress := flattenSecurityGroupRules(securityGroup.IpPermissions)
+	actualEgress := flattenSecurityGroupRules(securityGroup.IpPermissionsEgress)
+
+	t.Logf("🛡️  Verifying ingress rules...")
+	t.Logf("   Expected: %v", expectedIngress)
+	t.Logf("   Actual:   %v", actualIngress)
+	require.ElementsMatch(t, expectedIngress, actualIngress, "Security group ingress rules should match the module configuration exactly")
+
+	t.Logf("🛡️  Verifying egress rules...")
+	t.Logf("   Expected: %v", expectedEgress)
+	t.Logf("   Actual:   %v", actualEgress)
+	require.ElementsMatch(t, expectedEgress, actualEgress, "Security group egress rules should match the module configuration exactly")
+
+	// Verify the tasks use the module security group plus any additional ones
+	expectedTaskSGs := []string{securityGroupID}
+	if additionalSGs, ok := moduleOptions.Vars["additional_security_group_ids"].([]string); ok {
+		expectedTaskSGs = append(expectedTaskSGs, additionalSGs...)
+	}
+
+	ecsClient := newECSClient(t, region)
+	service, err := ecsClient.DescribeServices(&ecs.DescribeServicesInput{
+		Cluster:  aws.String(clusterName),
+		Services: []*string{aws.String(serviceName)},
+	})
+	require.NoError(t, err)
+	require.Len(t, service.Services, 1)
+	require.NotNil(t, service.Services[0].NetworkConfiguration)
+
+	taskSGs := aws.StringValueSlice(service.Services[0].NetworkConfiguration.AwsvpcConfiguration.SecurityGroups)
+	t.Logf("   Task Security Groups: %v (expected: %v)", taskSGs, expectedTaskSGs)
+	require.ElementsMatch(t, expectedTaskSGs, taskSGs, "Tasks should use the module security group plus additional_security_group_ids")
+}
+
+func testSecurityGroupCustomRules(t *testing.T, infraOutputs *InfrastructureOutputs, testName string) {
+	scenarioName := fmt.Sprintf("%s-sg", testName)
+
+	moduleOptions, err := deployModuleScenario(t, infraOutputs, scenarioName, func(vars map[string]interface{}) {
+		vars["allow_vpc_ingress"] = false
+		vars["security_group_ingress_rules"] = []map[string]interface{}{
+			{
+				"description": "Private subnets",
+				"cidr_blocks": []string{"10.0.10.0/24", "10.0.11.0/24"},
+			},
+			{
+				"description":              "Metrics from shared security group",
+				"source_security_group_id": infraOutputs.SharedSecurityGroupID,
+				"from_port":                9090,
+			},
+		}
+		vars["security_group_egress_rules"] = []map[string]interface{}{
+			{
+				"description": "HTTPS outbound",
+				"from_port":   443,
+				"to_port":     443,
+				"protocol":    "tcp",
+				"cidr_blocks": []string{"0.0.0.0/0"},
+			},
+		}
+		vars["additional_security_group_ids"] = []string{infraOutputs.SharedSecurityGroupID}
+	})
+	require.NoError(t, err, "Scenario with custom security group rules should apply")
+
+	testSecurityGroup(t, moduleOptions, infraOutputs)
+}
+
+// expectedSecurityGroupRules builds the ingress and egress rules the module should create from its vars
+// Rules use the same "protocol ports source" format as flattenSecurityGroupRules
+func expectedSecurityGroupRules(vars map[string]interface{}, infraOutputs *InfrastructureOutputs) ([]string, []string) {
+	containerPort := vars["container_port"].(int)
+	containerPorts := fmt.Sprintf("%d-%d", containerPort, containerPort)
+
+	var ingress []string
+	if albSGID, ok := vars["alb_security_group_id"].(string); ok && albSGID != "" {
+		ingress = append(ingress, fmt.Sprintf("tcp %s %s", containerPorts, albSGID))
+	}
+	if allowVPC, ok := vars["allow_vpc_ingress"].(bool); !ok || allowVPC {
+		ingress = append(ingress, fmt.Sprintf("tcp %s %s", containerPorts, infraOutputs.VPCCIDRBlock))
+	}
+	if ports, ok := vars["additional_ports"].([]map[string]interface{}); ok {
+		for _, port := range ports {
+			portNumber := port["port"].(int)
+			portRange := fmt.Sprintf("%d-%d", portNumber, portNumber)
+			protocol := "tcp"
+			if p, ok := port["protocol"].(string); ok {
+				protocol = p
+			}
+			if _, hasTargetGroup := port["target_group"]; hasTargetGroup {
+				ingress = append(ingress, fmt.Sprintf("tcp %s %s", portRange, vars["alb_security_group_id"]))
+			}
+			if allowVPC, ok := vars["allow_vpc_ingress"].(bool); !ok || allowVPC {
+				ingress = append(ingress, fmt.Sprintf("%s %s %s", protocol, portRange, infraOutputs.VPCCIDRBlock))
 			}
 		}
-		if *rule.IpProtocol == "tcp" && len(rule.IpRanges) > 0 {
-			for _, ipRange := range rule.IpRanges {
-				// Check if it's the VPC CIDR (10.0.0.0/16)
-				if *ipRange.CidrIp == "10.0.0.0/16" {
-					foundVPCRule = true
+	}
+	if nlb, ok := vars["nlb"].(map[string]interface{}); ok {
+		for _, protocol := range nlbContainerProtocols(nlb) {
+			if sgID, ok := nlb["security_group_id"].(string); ok {
+				ingress = append(ingress, fmt.Sprintf("%s %s %s", protocol, containerPorts, sgID))
+			}
+			if cidrs, ok := nlb["client_cidr_blocks"].([]string); ok {
+				for _, cidr := range cidrs {
+					ingress = append(ingress, fmt.Sprintf("%s %s %s", protocol, containerPorts, cidr))
 				}
 			}
 		}
 	}
-	if infraOutputs.ALBSecurityGroupID != "" {
-		require.True(t, foundALBRule, "Security group should allow traffic from ALB security group when ALB is configured")
-	} else {
-		t.Logf("   ALB not configured, skipping ALB security group rule check")
-	}
-	require.True(t, foundVPCRule, "Security group should allow traffic from VPC")
-
-	// Verify egress rules (should allow all outbound)
-	egressRules := securityGroup.IpPermissionsEgress
-	require.Greater(t, len(egressRules), 0)
-	foundEgressAll := false
-	for _, rule := range egressRules {
-		if *rule.IpProtocol == "-1" && len(rule.IpRanges) > 0 {
-			for _, ipRange := range rule.IpRanges {
-				if *ipRange.CidrIp == "0.0.0.0/0" {
-					foundEgressAll = true
-					break
-				}
+	if rules, ok := vars["security_group_ingress_rules"].([]map[string]interface{}); ok {
+		for _, rule := range rules {
+			protocol := "tcp"
+			if p, ok := rule["protocol"].(string); ok {
+				protocol = p
+			}
+			fromPort := containerPort
+			if p, ok := rule["from_port"].(int); ok {
+				fromPort = p
 			}
+			toPort := fromPort
+			if p, ok := rule["to_port"].(int); ok {
+				toPort = p
+			}
+			ingress = append(ingress, expandRuleSources(protocol, fromPort, toPort, rule)...)
+		}
+	}
+
+	egress := []string{"-1 all 0.0.0.0/0"}
+	if rules, ok := vars["security_group_egress_rules"].([]map[string]interface{}); ok {
+		egress = nil
+		for _, rule := range rules {
+			egress = append(egress, expandRuleSources(rule["protocol"].(string), rule["from_port"].(int), rule["to_port"].(int), rule)...)
+		}
+	}
+
+	return ingress, egress
+}
+
+// nlbContainerProtocols returns the container port protocols the module uses for an NLB protocol
+func nlbContainerProtocols(nlb map[string]interface{}) []string {
+	switch nlb["protocol"] {
+	case "UDP":
+		return []string{"udp"}
+	case "TCP_UDP":
+		return []string{"tcp", "udp"}
+	default:
+		return []string{"tcp"}
+	}
+}
+
+// expandRuleSources returns one entry per source (CIDR, prefix list or security group) of a rule
+func expandRuleSources(protocol string, fromPort, toPort int, rule map[string]interface{}) []string {
+	ports := "all"
+	if protocol != "-1" {
+		ports = fmt.Sprintf("%d-%d", fromPort, toPort)
+	}
+
+	var sources []string
+	if cidrs, ok := rule["cidr_blocks"].([]string); ok {
+		sources = append(sources, cidrs...)
+	}
+	if prefixLists, ok := rule["prefix_list_ids"].([]string); ok {
+		sources = append(sources, prefixLists...)
+	}
+	if sgID, ok := rule["source_security_group_id"].(string); ok {
+		sources = append(sources, sgID)
+	}
+	if sgIDs, ok := rule["security_groups"].([]string); ok {
+		sources = append(sources, sgIDs...)
+	}
+
+	var entries []string
+	for _, source := range sources {
+		entries = append(entries, fmt.Sprintf("%s %s %s", protocol, ports, source))
+	}
+	return entries
+}
+
+// flattenSecurityGroupRules converts EC2 permissions into "protocol ports source" entries
+// so rule sets can be compared regardless of how AWS groups them
+func flattenSecurityGroupRules(permissions []*ec2.IpPermission) []string {
+	var entries []string
+	for _, permission := range permissions {
+		protocol := aws.StringValue(permission.IpProtocol)
+		ports := "all"
+		if protocol != "-1" {
+			ports = fmt.Sprintf("%d-%d", aws.Int64Value(permission.FromPort), aws.Int64Value(permission.ToPort))
+		}
+
+		for _, ipRange := range permission.IpRanges {
+			entries = append(entries, fmt.Sprintf("%s %s %s", protocol, ports, aws.StringValue(ipRange.CidrIp)))
+		}
+		for _, ipv6Range := range permission.Ipv6Ranges {
+			entries = append(entries, fmt.Sprintf("%s %s %s", protocol, ports, aws.StringValue(ipv6Range.CidrIpv6)))
+		}
+		for _, prefixList := range permission.PrefixListIds {
+			entries = append(entries, fmt.Sprintf("%s %s %s", protocol, ports, aws.StringValue(prefixList.PrefixListId)))
+		}
+		for _, pair := range permission.UserIdGroupPairs {
+			entries = append(entries, fmt.Sprintf("%s %s %s", protocol, ports, aws.StringValue(pair.GroupId)))
 		}
 	}
-	require.True(t, foundEgressAll, "Security group should allow all outbound traffic")
+	return entries
 }
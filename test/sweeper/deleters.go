@@ -0,0 +1,617 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/aws/aws-sdk-go/service/applicationautoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/scheduler"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+// resourceKind describes how to find and delete one type of resource
+type resourceKind struct {
+	kind   string // "<service>:<type>" as returned by parseARN
+	filter string // Tagging API resource type filter, empty when listed another way (IAM roles, list)
+	global bool   // listed through the Tagging API in globalTaggingRegion
+	// list finds resources that can't be tagged (dashboards, schedules), assigned to a run by name
+	list    func(s *sweeper, namePrefix string) ([]*resource, error)
+	prepare func(s *sweeper, resources []*resource) error
+	delete  func(s *sweeper, r *resource) error
+}
+
+// resourceKinds is in deletion order: services before the load balancers, target groups and
+// clusters they use, NAT gateways before their Elastic IPs, and the network before the VPC.
+// Application Auto Scaling targets are not taggable in this SDK version, so they are deregistered
+// together with their ECS service (or cluster). Schedules run tasks in the cluster, so they go first.
+var resourceKinds = []resourceKind{
+	{kind: "scheduler:schedule", list: listSchedules, delete: deleteSchedule},
+	{kind: "ecs:service", filter: "ecs:service", delete: deleteECSService},
+	{kind: "cloudwatch:alarm", filter: "cloudwatch:alarm", delete: deleteAlarm},
+	{kind: "cloudwatch:dashboard", list: listDashboards, delete: deleteDashboard},
+	{kind: "servicediscovery:service", filter: "servicediscovery:service", delete: deleteServiceDiscoveryService},
+	{kind: "elasticloadbalancing:loadbalancer", filter: "elasticloadbalancing:loadbalancer", delete: deleteLoadBalancer},
+	{kind: "elasticloadbalancing:targetgroup", filter: "elasticloadbalancing:targetgroup", delete: deleteTargetGroup},
+	{kind: "acm:certificate", filter: "acm:certificate", delete: deleteCertificate},
+	{kind: "ecs:cluster", filter: "ecs:cluster", delete: deleteECSCluster},
+	{kind: "ec2:natgateway", filter: "ec2:natgateway", delete: deleteNatGateway},
+	{kind: "ec2:elastic-ip", filter: "ec2:elastic-ip", delete: releaseElasticIP},
+	{kind: "ec2:security-group", filter: "ec2:security-group", prepare: revokeSecurityGroupRules, delete: deleteSecurityGroup},
+	{kind: "ec2:internet-gateway", filter: "ec2:internet-gateway", delete: deleteInternetGateway},
+	{kind: "ec2:subnet", filter: "ec2:subnet", delete: deleteSubnet},
+	{kind: "ec2:route-table", filter: "ec2:route-table", delete: deleteRouteTable},
+	{kind: "route53:hostedzone", filter: "route53:hostedzone", global: true, delete: deleteHostedZone},
+	{kind: "ec2:vpc", filter: "ec2:vpc", delete: deleteVPC},
+	{kind: "iam:role", delete: deleteIAMRole},
+	{kind: "logs:log-group", filter: "logs:log-group", delete: deleteLogGroup},
+	{kind: "secretsmanager:secret", filter: "secretsmanager:secret", delete: deleteSecret},
+	{kind: "ssm:parameter", filter: "ssm:parameter", delete: deleteSSMParameter},
+	{kind: "sns:topic", filter: "sns", delete: deleteSNSTopic},
+	{kind: "kms:key", filter: "kms:key", delete: deleteKMSKey},
+	{kind: "s3:bucket", filter: "s3", delete: deleteS3Bucket},
+}
+
+func findKind(kind string) (resourceKind, bool) {
+	for _, k := range resourceKinds {
+		if k.kind == kind {
+			return k, true
+		}
+	}
+	return resourceKind{}, false
+}
+
+// Errors returned while a dependency is still being released (ENIs of stopping tasks, draining
+// load balancers, instances being deregistered...): the delete is retried
+var retryableCodes = []string{
+	"DependencyViolation",
+	"ResourceInUse",
+	"ResourceInUseException",
+	"ClusterContainsTasksException",
+	"ClusterContainsServicesException",
+	"DeleteConflict",
+	"InvalidIPAddress.InUse",
+	"BucketNotEmpty",
+}
+
+// Errors meaning the resource is already gone: the delete counts as done
+var notFoundCodes = []string{
+	"ServiceNotFoundException",
+	"ServiceNotActiveException",
+	"ClusterNotFoundException",
+	"ServiceNotFound",
+	"LoadBalancerNotFound",
+	"TargetGroupNotFound",
+	"NatGatewayNotFound",
+	"InvalidAllocationID.NotFound",
+	"InvalidGroup.NotFound",
+	"InvalidInternetGatewayID.NotFound",
+	"InvalidSubnetID.NotFound",
+	"InvalidRouteTableID.NotFound",
+	"InvalidVpcID.NotFound",
+	"NoSuchHostedZone",
+	"NoSuchEntity",
+	"ResourceNotFoundException",
+	"ParameterNotFound",
+	"NotFound",
+	"NotFoundException",
+	"NoSuchBucket",
+	"ResourceNotFound",
+}
+
+const (
+	retryAttempts = 10
+	retryDelay    = 30 * time.Second
+)
+
+// sweep deletes the resources kind by kind in resourceKinds order and returns the number of failures
+func (s *sweeper) sweep(resources []*resource) int {
+	failed := 0
+	for _, kind := range resourceKinds {
+		var batch []*resource
+		for _, r := range resources {
+			if r.Kind == kind.kind {
+				batch = append(batch, r)
+			}
+		}
+		if len(batch) == 0 {
+			continue
+		}
+
+		fmt.Printf("🗑️  Deleting %d %s\n", len(batch), kind.kind)
+		if kind.prepare != nil {
+			if err := kind.prepare(s, batch); err != nil {
+				fmt.Printf("⚠️  Could not prepare %s deletion: %v\n", kind.kind, err)
+			}
+		}
+
+		for _, r := range batch {
+			err := retry(func() error { return kind.delete(s, r) })
+			if err != nil && !isAWSErrorCode(err, notFoundCodes...) {
+				fmt.Printf("   ❌ %s: %v\n", r.ARN, err)
+				failed++
+				continue
+			}
+			fmt.Printf("   ✓ %s\n", r.ARN)
+		}
+	}
+	return failed
+}
+
+func retry(action func() error) error {
+	var err error
+	for attempt := 1; attempt <= retryAttempts; attempt++ {
+		err = action()
+		if err == nil || !isAWSErrorCode(err, retryableCodes...) {
+			return err
+		}
+		if attempt < retryAttempts {
+			fmt.Printf("   ⏳ %v, retrying in %s (%d/%d)\n", err, retryDelay, attempt, retryAttempts)
+			time.Sleep(retryDelay)
+		}
+	}
+	return err
+}
+
+func deleteECSService(s *sweeper, r *resource) error {
+	cluster, service, err := splitECSService(r.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.deleteECSServiceInCluster(cluster, service); err != nil {
+		return err
+	}
+	resourceID := fmt.Sprintf("service/%s/%s", cluster, service)
+	return s.deregisterScalableTargets(func(id string) bool { return id == resourceID })
+}
+
+func (s *sweeper) deleteECSServiceInCluster(cluster, service string) error {
+	// Scale to zero first so the tasks start draining; fails harmlessly on inactive services
+	_, _ = s.ecs.UpdateService(&ecs.UpdateServiceInput{
+		Cluster:      aws.String(cluster),
+		Service:      aws.String(service),
+		DesiredCount: aws.Int64(0),
+	})
+	_, err := s.ecs.DeleteService(&ecs.DeleteServiceInput{
+		Cluster: aws.String(cluster),
+		Service: aws.String(service),
+		Force:   aws.Bool(true),
+	})
+	if isAWSErrorCode(err, notFoundCodes...) {
+		return nil
+	}
+	return err
+}
+
+func (s *sweeper) deregisterScalableTargets(match func(resourceID string) bool) error {
+	var targets []*applicationautoscaling.ScalableTarget
+	input := &applicationautoscaling.DescribeScalableTargetsInput{
+		ServiceNamespace: aws.String(applicationautoscaling.ServiceNamespaceEcs),
+	}
+	err := s.autoscaling.DescribeScalableTargetsPages(input, func(page *applicationautoscaling.DescribeScalableTargetsOutput, lastPage bool) bool {
+		for _, target := range page.ScalableTargets {
+			if match(aws.StringValue(target.ResourceId)) {
+				targets = append(targets, target)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, target := range targets {
+		_, err := s.autoscaling.DeregisterScalableTarget(&applicationautoscaling.DeregisterScalableTargetInput{
+			ServiceNamespace:  target.ServiceNamespace,
+			ResourceId:        target.ResourceId,
+			ScalableDimension: target.ScalableDimension,
+		})
+		if err != nil && !isAWSErrorCode(err, applicationautoscaling.ErrCodeObjectNotFoundException) {
+			return err
+		}
+	}
+	return nil
+}
+
+func deleteSchedule(s *sweeper, r *resource) error {
+	group, name, err := splitSchedule(r.ID)
+	if err != nil {
+		return err
+	}
+	_, err = s.scheduler.DeleteSchedule(&scheduler.DeleteScheduleInput{
+		GroupName: aws.String(group),
+		Name:      aws.String(name),
+	})
+	return err
+}
+
+func deleteAlarm(s *sweeper, r *resource) error {
+	_, err := s.cloudwatch.DeleteAlarms(&cloudwatch.DeleteAlarmsInput{AlarmNames: aws.StringSlice([]string{r.ID})})
+	return err
+}
+
+func deleteDashboard(s *sweeper, r *resource) error {
+	_, err := s.cloudwatch.DeleteDashboards(&cloudwatch.DeleteDashboardsInput{DashboardNames: aws.StringSlice([]string{r.ID})})
+	return err
+}
+
+func deleteServiceDiscoveryService(s *sweeper, r *resource) error {
+	var instanceIDs []*string
+	input := &servicediscovery.ListInstancesInput{ServiceId: aws.String(r.ID)}
+	err := s.servicediscovery.ListInstancesPages(input, func(page *servicediscovery.ListInstancesOutput, lastPage bool) bool {
+		for _, instance := range page.Instances {
+			instanceIDs = append(instanceIDs, instance.Id)
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, instanceID := range instanceIDs {
+		_, err := s.servicediscovery.DeregisterInstance(&servicediscovery.DeregisterInstanceInput{
+			ServiceId:  aws.String(r.ID),
+			InstanceId: instanceID,
+		})
+		if err != nil && !isAWSErrorCode(err, servicediscovery.ErrCodeInstanceNotFound) {
+			return err
+		}
+	}
+
+	// Deregistration is asynchronous: ResourceInUse until it finishes (retried)
+	_, err = s.servicediscovery.DeleteService(&servicediscovery.DeleteServiceInput{Id: aws.String(r.ID)})
+	return err
+}
+
+func deleteLoadBalancer(s *sweeper, r *resource) error {
+	var listenerARNs []*string
+	input := &elbv2.DescribeListenersInput{LoadBalancerArn: aws.String(r.ARN)}
+	err := s.elbv2.DescribeListenersPages(input, func(page *elbv2.DescribeListenersOutput, lastPage bool) bool {
+		for _, listener := range page.Listeners {
+			listenerARNs = append(listenerARNs, listener.ListenerArn)
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, listenerARN := range listenerARNs {
+		if _, err := s.elbv2.DeleteListener(&elbv2.DeleteListenerInput{ListenerArn: listenerARN}); err != nil && !isAWSErrorCode(err, elbv2.ErrCodeListenerNotFoundException) {
+			return err
+		}
+	}
+
+	if _, err := s.elbv2.DeleteLoadBalancer(&elbv2.DeleteLoadBalancerInput{LoadBalancerArn: aws.String(r.ARN)}); err != nil {
+		return err
+	}
+	// Target groups and certificates stay in use until the load balancer is gone
+	return s.elbv2.WaitUntilLoadBalancersDeleted(&elbv2.DescribeLoadBalancersInput{LoadBalancerArns: aws.StringSlice([]string{r.ARN})})
+}
+
+func deleteTargetGroup(s *sweeper, r *resource) error {
+	_, err := s.elbv2.DeleteTargetGroup(&elbv2.DeleteTargetGroupInput{TargetGroupArn: aws.String(r.ARN)})
+	return err
+}
+
+func deleteCertificate(s *sweeper, r *resource) error {
+	_, err := s.acm.DeleteCertificate(&acm.DeleteCertificateInput{CertificateArn: aws.String(r.ARN)})
+	return err
+}
+
+// deleteECSCluster also removes services and scaling targets left in the cluster by module runs
+// whose own tags were not found (e.g. a service deleted outside Terraform)
+func deleteECSCluster(s *sweeper, r *resource) error {
+	var serviceARNs []string
+	input := &ecs.ListServicesInput{Cluster: aws.String(r.ID)}
+	err := s.ecs.ListServicesPages(input, func(page *ecs.ListServicesOutput, lastPage bool) bool {
+		serviceARNs = append(serviceARNs, aws.StringValueSlice(page.ServiceArns)...)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, serviceARN := range serviceARNs {
+		if err := s.deleteECSServiceInCluster(r.ID, serviceARN); err != nil {
+			return err
+		}
+	}
+
+	prefix := fmt.Sprintf("service/%s/", r.ID)
+	if err := s.deregisterScalableTargets(func(id string) bool { return strings.HasPrefix(id, prefix) }); err != nil {
+		return err
+	}
+
+	// ClusterContainsTasksException while the tasks stop (retried)
+	_, err = s.ecs.DeleteCluster(&ecs.DeleteClusterInput{Cluster: aws.String(r.ID)})
+	return err
+}
+
+func deleteNatGateway(s *sweeper, r *resource) error {
+	if _, err := s.ec2.DeleteNatGateway(&ec2.DeleteNatGatewayInput{NatGatewayId: aws.String(r.ID)}); err != nil {
+		return err
+	}
+	// The Elastic IP and the subnet stay in use until the NAT gateway is deleted
+	return s.ec2.WaitUntilNatGatewayDeleted(&ec2.DescribeNatGatewaysInput{NatGatewayIds: aws.StringSlice([]string{r.ID})})
+}
+
+func releaseElasticIP(s *sweeper, r *resource) error {
+	_, err := s.ec2.ReleaseAddress(&ec2.ReleaseAddressInput{AllocationId: aws.String(r.ID)})
+	return err
+}
+
+// revokeSecurityGroupRules removes every rule first, so groups referencing each other
+// (module service SG <-> fixture ALB/NLB SGs) can then be deleted in any order
+func revokeSecurityGroupRules(s *sweeper, resources []*resource) error {
+	for _, r := range resources {
+		output, err := s.ec2.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{GroupIds: aws.StringSlice([]string{r.ID})})
+		if err != nil {
+			if isAWSErrorCode(err, notFoundCodes...) {
+				continue
+			}
+			return err
+		}
+
+		for _, group := range output.SecurityGroups {
+			if len(group.IpPermissions) > 0 {
+				_, err := s.ec2.RevokeSecurityGroupIngress(&ec2.RevokeSecurityGroupIngressInput{
+					GroupId:       group.GroupId,
+					IpPermissions: group.IpPermissions,
+				})
+				if err != nil {
+					return fmt.Errorf("revoking ingress rules of %s: %w", r.ID, err)
+				}
+			}
+			if len(group.IpPermissionsEgress) > 0 {
+				_, err := s.ec2.RevokeSecurityGroupEgress(&ec2.RevokeSecurityGroupEgressInput{
+					GroupId:       group.GroupId,
+					IpPermissions: group.IpPermissionsEgress,
+				})
+				if err != nil {
+					return fmt.Errorf("revoking egress rules of %s: %w", r.ID, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func deleteSecurityGroup(s *sweeper, r *resource) error {
+	// DependencyViolation while task or load balancer ENIs are released (retried)
+	_, err := s.ec2.DeleteSecurityGroup(&ec2.DeleteSecurityGroupInput{GroupId: aws.String(r.ID)})
+	return err
+}
+
+func deleteInternetGateway(s *sweeper, r *resource) error {
+	output, err := s.ec2.DescribeInternetGateways(&ec2.DescribeInternetGatewaysInput{InternetGatewayIds: aws.StringSlice([]string{r.ID})})
+	if err != nil {
+		return err
+	}
+
+	for _, gateway := range output.InternetGateways {
+		for _, attachment := range gateway.Attachments {
+			_, err := s.ec2.DetachInternetGateway(&ec2.DetachInternetGatewayInput{
+				InternetGatewayId: gateway.InternetGatewayId,
+				VpcId:             attachment.VpcId,
+			})
+			if err != nil && !isAWSErrorCode(err, "Gateway.NotAttached") {
+				return err
+			}
+		}
+	}
+
+	_, err = s.ec2.DeleteInternetGateway(&ec2.DeleteInternetGatewayInput{InternetGatewayId: aws.String(r.ID)})
+	return err
+}
+
+func deleteSubnet(s *sweeper, r *resource) error {
+	_, err := s.ec2.DeleteSubnet(&ec2.DeleteSubnetInput{SubnetId: aws.String(r.ID)})
+	return err
+}
+
+func deleteRouteTable(s *sweeper, r *resource) error {
+	output, err := s.ec2.DescribeRouteTables(&ec2.DescribeRouteTablesInput{RouteTableIds: aws.StringSlice([]string{r.ID})})
+	if err != nil {
+		return err
+	}
+
+	for _, table := range output.RouteTables {
+		for _, association := range table.Associations {
+			// The main route table goes away with its VPC
+			if aws.BoolValue(association.Main) {
+				return nil
+			}
+			_, err := s.ec2.DisassociateRouteTable(&ec2.DisassociateRouteTableInput{AssociationId: association.RouteTableAssociationId})
+			if err != nil && !isAWSErrorCode(err, "InvalidAssociationID.NotFound") {
+				return err
+			}
+		}
+	}
+
+	_, err = s.ec2.DeleteRouteTable(&ec2.DeleteRouteTableInput{RouteTableId: aws.String(r.ID)})
+	return err
+}
+
+func deleteHostedZone(s *sweeper, r *resource) error {
+	zone, err := s.route53.GetHostedZone(&route53.GetHostedZoneInput{Id: aws.String(r.ID)})
+	if err != nil {
+		return err
+	}
+	zoneName := aws.StringValue(zone.HostedZone.Name)
+
+	// Every record except the apex SOA and NS, which are deleted with the zone
+	var changes []*route53.Change
+	input := &route53.ListResourceRecordSetsInput{HostedZoneId: aws.String(r.ID)}
+	err = s.route53.ListResourceRecordSetsPages(input, func(page *route53.ListResourceRecordSetsOutput, lastPage bool) bool {
+		for _, record := range page.ResourceRecordSets {
+			recordType := aws.StringValue(record.Type)
+			if aws.StringValue(record.Name) == zoneName && (recordType == route53.RRTypeSoa || recordType == route53.RRTypeNs) {
+				continue
+			}
+			changes = append(changes, &route53.Change{
+				Action:            aws.String(route53.ChangeActionDelete),
+				ResourceRecordSet: record,
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(changes) > 0 {
+		_, err := s.route53.ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+			HostedZoneId: aws.String(r.ID),
+			ChangeBatch:  &route53.ChangeBatch{Changes: changes},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = s.route53.DeleteHostedZone(&route53.DeleteHostedZoneInput{Id: aws.String(r.ID)})
+	return err
+}
+
+func deleteVPC(s *sweeper, r *resource) error {
+	_, err := s.ec2.DeleteVpc(&ec2.DeleteVpcInput{VpcId: aws.String(r.ID)})
+	return err
+}
+
+func deleteIAMRole(s *sweeper, r *resource) error {
+	roleName := aws.String(r.ID)
+
+	var attachedPolicyARNs []*string
+	err := s.iam.ListAttachedRolePoliciesPages(&iam.ListAttachedRolePoliciesInput{RoleName: roleName}, func(page *iam.ListAttachedRolePoliciesOutput, lastPage bool) bool {
+		for _, policy := range page.AttachedPolicies {
+			attachedPolicyARNs = append(attachedPolicyARNs, policy.PolicyArn)
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	for _, policyARN := range attachedPolicyARNs {
+		if _, err := s.iam.DetachRolePolicy(&iam.DetachRolePolicyInput{RoleName: roleName, PolicyArn: policyARN}); err != nil {
+			return err
+		}
+	}
+
+	var inlinePolicyNames []*string
+	err = s.iam.ListRolePoliciesPages(&iam.ListRolePoliciesInput{RoleName: roleName}, func(page *iam.ListRolePoliciesOutput, lastPage bool) bool {
+		inlinePolicyNames = append(inlinePolicyNames, page.PolicyNames...)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	for _, policyName := range inlinePolicyNames {
+		if _, err := s.iam.DeleteRolePolicy(&iam.DeleteRolePolicyInput{RoleName: roleName, PolicyName: policyName}); err != nil {
+			return err
+		}
+	}
+
+	profiles, err := s.iam.ListInstanceProfilesForRole(&iam.ListInstanceProfilesForRoleInput{RoleName: roleName})
+	if err != nil {
+		return err
+	}
+	for _, profile := range profiles.InstanceProfiles {
+		_, err := s.iam.RemoveRoleFromInstanceProfile(&iam.RemoveRoleFromInstanceProfileInput{
+			RoleName:            roleName,
+			InstanceProfileName: profile.InstanceProfileName,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = s.iam.DeleteRole(&iam.DeleteRoleInput{RoleName: roleName})
+	return err
+}
+
+func deleteLogGroup(s *sweeper, r *resource) error {
+	_, err := s.logs.DeleteLogGroup(&cloudwatchlogs.DeleteLogGroupInput{LogGroupName: aws.String(r.ID)})
+	return err
+}
+
+func deleteSecret(s *sweeper, r *resource) error {
+	_, err := s.secretsmanager.DeleteSecret(&secretsmanager.DeleteSecretInput{
+		SecretId:                   aws.String(r.ARN),
+		ForceDeleteWithoutRecovery: aws.Bool(true),
+	})
+	return err
+}
+
+func deleteSSMParameter(s *sweeper, r *resource) error {
+	// The ARN drops the leading slash of hierarchical names ("/ecs/<prefix>/API_KEY")
+	_, err := s.ssm.DeleteParameter(&ssm.DeleteParameterInput{Name: aws.String("/" + r.ID)})
+	if isAWSErrorCode(err, ssm.ErrCodeParameterNotFound) {
+		_, err = s.ssm.DeleteParameter(&ssm.DeleteParameterInput{Name: aws.String(r.ID)})
+	}
+	return err
+}
+
+func deleteSNSTopic(s *sweeper, r *resource) error {
+	_, err := s.sns.DeleteTopic(&sns.DeleteTopicInput{TopicArn: aws.String(r.ARN)})
+	return err
+}
+
+func deleteKMSKey(s *sweeper, r *resource) error {
+	_, err := s.kms.ScheduleKeyDeletion(&kms.ScheduleKeyDeletionInput{
+		KeyId:               aws.String(r.ARN),
+		PendingWindowInDays: aws.Int64(7),
+	})
+	return err
+}
+
+// deleteS3Bucket empties the bucket (every version and delete marker) before deleting it
+func deleteS3Bucket(s *sweeper, r *resource) error {
+	bucket := aws.String(r.ID)
+
+	var objects []*s3.ObjectIdentifier
+	err := s.s3.ListObjectVersionsPages(&s3.ListObjectVersionsInput{Bucket: bucket}, func(page *s3.ListObjectVersionsOutput, lastPage bool) bool {
+		for _, version := range page.Versions {
+			objects = append(objects, &s3.ObjectIdentifier{Key: version.Key, VersionId: version.VersionId})
+		}
+		for _, marker := range page.DeleteMarkers {
+			objects = append(objects, &s3.ObjectIdentifier{Key: marker.Key, VersionId: marker.VersionId})
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	// DeleteObjects accepts up to 1000 keys per call
+	for start := 0; start < len(objects); start += 1000 {
+		end := start + 1000
+		if end > len(objects) {
+			end = len(objects)
+		}
+		_, err := s.s3.DeleteObjects(&s3.DeleteObjectsInput{
+			Bucket: bucket,
+			Delete: &s3.Delete{Objects: objects[start:end], Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = s.s3.DeleteBucket(&s3.DeleteBucketInput{Bucket: bucket})
+	return err
+}
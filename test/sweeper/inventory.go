@@ -0,0 +1,494 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/aws/aws-sdk-go/service/applicationautoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/scheduler"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+// Tags set by the fixtures and by setupModuleOptions (common_tags)
+const (
+	managedByTag   = "ManagedBy"
+	managedByValue = "terratest"
+	testNameTag    = "TestName"
+)
+
+// globalTaggingRegion is where the Tagging API reports global resources (Route 53 hosted zones)
+const globalTaggingRegion = "us-east-1"
+
+// resource is a tagged resource found by the inventory
+type resource struct {
+	ARN      string
+	Kind     string // "<service>:<type>" as in the Tagging API resource type filters, e.g. "ec2:natgateway"
+	ID       string // ARN resource part after the type, e.g. "nat-0123" or "<cluster>/<service>" for ECS services
+	TestName string
+	Created  time.Time // zero when the service does not expose a creation date
+}
+
+type sweeper struct {
+	region string
+
+	tagging          *resourcegroupstaggingapi.ResourceGroupsTaggingAPI
+	globalTagging    *resourcegroupstaggingapi.ResourceGroupsTaggingAPI
+	ecs              *ecs.ECS
+	scheduler        *scheduler.Scheduler
+	cloudwatch       *cloudwatch.CloudWatch
+	autoscaling      *applicationautoscaling.ApplicationAutoScaling
+	servicediscovery *servicediscovery.ServiceDiscovery
+	elbv2            *elbv2.ELBV2
+	acm              *acm.ACM
+	ec2              *ec2.EC2
+	route53          *route53.Route53
+	iam              *iam.IAM
+	logs             *cloudwatchlogs.CloudWatchLogs
+	secretsmanager   *secretsmanager.SecretsManager
+	ssm              *ssm.SSM
+	sns              *sns.SNS
+	kms              *kms.KMS
+	s3               *s3.S3
+}
+
+func newSweeper(region string) (*sweeper, error) {
+	sess, err := newSession(region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS session: %w", err)
+	}
+	globalSess, err := newSession(globalTaggingRegion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS session: %w", err)
+	}
+
+	return &sweeper{
+		region:           region,
+		tagging:          resourcegroupstaggingapi.New(sess),
+		globalTagging:    resourcegroupstaggingapi.New(globalSess),
+		ecs:              ecs.New(sess),
+		scheduler:        scheduler.New(sess),
+		cloudwatch:       cloudwatch.New(sess),
+		autoscaling:      applicationautoscaling.New(sess),
+		servicediscovery: servicediscovery.New(sess),
+		elbv2:            elbv2.New(sess),
+		acm:              acm.New(sess),
+		ec2:              ec2.New(sess),
+		route53:          route53.New(sess),
+		iam:              iam.New(sess),
+		logs:             cloudwatchlogs.New(sess),
+		secretsmanager:   secretsmanager.New(sess),
+		ssm:              ssm.New(sess),
+		sns:              sns.New(sess),
+		kms:              kms.New(sess),
+		s3:               s3.New(sess),
+	}, nil
+}
+
+// newSession uses the default credential chain, or LocalStack when TEST_BACKEND=localstack (same switch as the suite)
+func newSession(region string) (*session.Session, error) {
+	config := aws.NewConfig().WithRegion(region)
+	if os.Getenv("TEST_BACKEND") == "localstack" {
+		endpoint := os.Getenv("LOCALSTACK_ENDPOINT")
+		if endpoint == "" {
+			endpoint = "http://localhost.localstack.cloud:4566"
+		}
+		config = config.
+			WithEndpoint(endpoint).
+			WithCredentials(credentials.NewStaticCredentials("test", "test", "")).
+			WithS3ForcePathStyle(true)
+	}
+
+	return session.NewSessionWithOptions(session.Options{
+		Config:            *config,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+}
+
+// inventory lists every resource tagged ManagedBy=terratest that belongs to a test run (has a TestName tag)
+func (s *sweeper) inventory(testName string) ([]*resource, error) {
+	// TestName is filtered here and not in the query: scenario resources are tagged <test name>-<scenario>
+	tagFilters := []*resourcegroupstaggingapi.TagFilter{
+		{Key: aws.String(managedByTag), Values: aws.StringSlice([]string{managedByValue})},
+	}
+
+	var regionalFilters, globalFilters []string
+	for _, kind := range resourceKinds {
+		switch {
+		case kind.filter == "":
+		case kind.global:
+			globalFilters = append(globalFilters, kind.filter)
+		default:
+			regionalFilters = append(regionalFilters, kind.filter)
+		}
+	}
+
+	var resources []*resource
+	for _, query := range []struct {
+		client  *resourcegroupstaggingapi.ResourceGroupsTaggingAPI
+		filters []string
+	}{
+		{s.tagging, regionalFilters},
+		{s.globalTagging, globalFilters},
+	} {
+		input := &resourcegroupstaggingapi.GetResourcesInput{
+			ResourceTypeFilters: aws.StringSlice(query.filters),
+			TagFilters:          tagFilters,
+		}
+		err := query.client.GetResourcesPages(input, func(page *resourcegroupstaggingapi.GetResourcesOutput, lastPage bool) bool {
+			for _, mapping := range page.ResourceTagMappingList {
+				tags := map[string]string{}
+				for _, tag := range mapping.Tags {
+					tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+				}
+				if !belongsToRun(tags[testNameTag], testName) {
+					continue
+				}
+				if r := newResource(aws.StringValue(mapping.ResourceARN), tags[testNameTag]); r != nil {
+					resources = append(resources, r)
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The Tagging API does not list IAM roles
+	roles, err := s.taggedRoles(testName)
+	if err != nil {
+		return nil, err
+	}
+	resources = append(resources, roles...)
+
+	untagged, err := s.untaggedResources(testName, resources)
+	if err != nil {
+		return nil, err
+	}
+	resources = append(resources, untagged...)
+
+	var live []*resource
+	for _, r := range resources {
+		keep, err := s.inspect(r)
+		if err != nil {
+			fmt.Printf("⚠️  Could not inspect %s: %v\n", r.ARN, err)
+		}
+		if keep {
+			live = append(live, r)
+		}
+	}
+	return live, nil
+}
+
+// newResource returns nil for resources outside a test run (no TestName, e.g. the state backend) or of unknown kind
+func newResource(arn, testName string) *resource {
+	if testName == "" {
+		return nil
+	}
+	kind, id := parseARN(arn)
+	if _, ok := findKind(kind); !ok {
+		return nil
+	}
+	return &resource{ARN: arn, Kind: kind, ID: id, TestName: testName}
+}
+
+// belongsToRun reports whether a TestName tag belongs to the run selected with -test-name (any run when empty):
+// the suite tags the fixtures and the main module with the run name, and each scenario with "<run name>-<suffix>"
+func belongsToRun(testNameTagValue, runName string) bool {
+	return runName == "" || testNameTagValue == runName || strings.HasPrefix(testNameTagValue, runName+"-")
+}
+
+// parseARN splits the resource part of an ARN into the Tagging API type and the resource ID:
+// "arn:aws:ec2:us-west-2:123:natgateway/nat-1" -> ("ec2:natgateway", "nat-1")
+func parseARN(arn string) (kind, id string) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) < 6 {
+		return "", ""
+	}
+	service, resourcePart := parts[2], parts[5]
+
+	if i := strings.IndexAny(resourcePart, "/:"); i >= 0 {
+		return service + ":" + resourcePart[:i], strings.TrimSuffix(resourcePart[i+1:], ":*")
+	}
+
+	// SNS topics and S3 buckets have no type in the ARN
+	switch service {
+	case "sns":
+		return "sns:topic", resourcePart
+	case "s3":
+		return "s3:bucket", resourcePart
+	}
+	return service + ":" + resourcePart, ""
+}
+
+// untaggedResources lists the resources that can't be tagged (dashboards, schedules) and assigns each
+// to the run its name starts with. Only runs found through their tagged resources, or the one selected
+// with -test-name, are considered, so resources outside the test suite are never picked up.
+func (s *sweeper) untaggedResources(testName string, tagged []*resource) ([]*resource, error) {
+	runs := map[string]bool{}
+	for _, r := range tagged {
+		runs[r.TestName] = true
+	}
+	if testName != "" {
+		runs[testName] = true
+	}
+
+	var resources []*resource
+	for _, kind := range resourceKinds {
+		if kind.list == nil {
+			continue
+		}
+		listed, err := kind.list(s, testName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", kind.kind, err)
+		}
+		for _, r := range listed {
+			if r.TestName = runOfName(r.ID[strings.LastIndex(r.ID, "/")+1:], runs); r.TestName != "" {
+				resources = append(resources, r)
+			}
+		}
+	}
+	return resources, nil
+}
+
+// runOfName returns the longest run name followed by "-" that prefixes name (scenario runs are
+// "<run name>-<suffix>"), or "" when the name belongs to none of the runs
+func runOfName(name string, runs map[string]bool) string {
+	run := ""
+	for candidate := range runs {
+		if strings.HasPrefix(name, candidate+"-") && len(candidate) > len(run) {
+			run = candidate
+		}
+	}
+	return run
+}
+
+func listDashboards(s *sweeper, namePrefix string) ([]*resource, error) {
+	input := &cloudwatch.ListDashboardsInput{}
+	if namePrefix != "" {
+		input.DashboardNamePrefix = aws.String(namePrefix)
+	}
+
+	var resources []*resource
+	err := s.cloudwatch.ListDashboardsPages(input, func(page *cloudwatch.ListDashboardsOutput, lastPage bool) bool {
+		for _, dashboard := range page.DashboardEntries {
+			resources = append(resources, &resource{
+				ARN:  aws.StringValue(dashboard.DashboardArn),
+				Kind: "cloudwatch:dashboard",
+				ID:   aws.StringValue(dashboard.DashboardName),
+			})
+		}
+		return true
+	})
+	return resources, err
+}
+
+func listSchedules(s *sweeper, namePrefix string) ([]*resource, error) {
+	input := &scheduler.ListSchedulesInput{}
+	if namePrefix != "" {
+		input.NamePrefix = aws.String(namePrefix)
+	}
+
+	var resources []*resource
+	err := s.scheduler.ListSchedulesPages(input, func(page *scheduler.ListSchedulesOutput, lastPage bool) bool {
+		for _, schedule := range page.Schedules {
+			resources = append(resources, &resource{
+				ARN:     aws.StringValue(schedule.Arn),
+				Kind:    "scheduler:schedule",
+				ID:      fmt.Sprintf("%s/%s", aws.StringValue(schedule.GroupName), aws.StringValue(schedule.Name)),
+				Created: aws.TimeValue(schedule.CreationDate),
+			})
+		}
+		return true
+	})
+	return resources, err
+}
+
+func (s *sweeper) taggedRoles(testName string) ([]*resource, error) {
+	var roles []*iam.Role
+	err := s.iam.ListRolesPages(&iam.ListRolesInput{}, func(page *iam.ListRolesOutput, lastPage bool) bool {
+		for _, role := range page.Roles {
+			if !strings.HasPrefix(aws.StringValue(role.Path), "/aws-service-role/") {
+				roles = append(roles, role)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*resource
+	for _, role := range roles {
+		output, err := s.iam.ListRoleTags(&iam.ListRoleTagsInput{RoleName: role.RoleName})
+		if err != nil {
+			if isAWSErrorCode(err, iam.ErrCodeNoSuchEntityException) {
+				continue
+			}
+			return nil, err
+		}
+
+		tags := map[string]string{}
+		for _, tag := range output.Tags {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+		if tags[managedByTag] != managedByValue || tags[testNameTag] == "" {
+			continue
+		}
+		if !belongsToRun(tags[testNameTag], testName) {
+			continue
+		}
+
+		resources = append(resources, &resource{
+			ARN:      aws.StringValue(role.Arn),
+			Kind:     "iam:role",
+			ID:       aws.StringValue(role.RoleName),
+			TestName: tags[testNameTag],
+			Created:  aws.TimeValue(role.CreateDate),
+		})
+	}
+	return resources, nil
+}
+
+// inspect fills the creation date where the service exposes one and drops resources that are
+// already gone but still reported by the Tagging API (inactive services, deleted NAT gateways,
+// KMS keys pending deletion...)
+func (s *sweeper) inspect(r *resource) (bool, error) {
+	switch r.Kind {
+	case "ecs:service":
+		cluster, service, err := splitECSService(r.ID)
+		if err != nil {
+			return false, err
+		}
+		output, err := s.ecs.DescribeServices(&ecs.DescribeServicesInput{
+			Cluster:  aws.String(cluster),
+			Services: aws.StringSlice([]string{service}),
+		})
+		if err != nil {
+			return !isAWSErrorCode(err, ecs.ErrCodeClusterNotFoundException), err
+		}
+		if len(output.Services) == 0 || aws.StringValue(output.Services[0].Status) == "INACTIVE" {
+			return false, nil
+		}
+		r.Created = aws.TimeValue(output.Services[0].CreatedAt)
+
+	case "ecs:cluster":
+		output, err := s.ecs.DescribeClusters(&ecs.DescribeClustersInput{Clusters: aws.StringSlice([]string{r.ARN})})
+		if err != nil {
+			return true, err
+		}
+		if len(output.Clusters) == 0 || aws.StringValue(output.Clusters[0].Status) == "INACTIVE" {
+			return false, nil
+		}
+
+	case "elasticloadbalancing:loadbalancer":
+		output, err := s.elbv2.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{LoadBalancerArns: aws.StringSlice([]string{r.ARN})})
+		if err != nil {
+			return !isAWSErrorCode(err, elbv2.ErrCodeLoadBalancerNotFoundException), err
+		}
+		if len(output.LoadBalancers) == 0 {
+			return false, nil
+		}
+		r.Created = aws.TimeValue(output.LoadBalancers[0].CreatedTime)
+
+	case "ec2:natgateway":
+		output, err := s.ec2.DescribeNatGateways(&ec2.DescribeNatGatewaysInput{NatGatewayIds: aws.StringSlice([]string{r.ID})})
+		if err != nil {
+			return !isAWSErrorCode(err, "NatGatewayNotFound"), err
+		}
+		if len(output.NatGateways) == 0 {
+			return false, nil
+		}
+		switch aws.StringValue(output.NatGateways[0].State) {
+		case ec2.NatGatewayStateDeleted, ec2.NatGatewayStateDeleting:
+			return false, nil
+		}
+		r.Created = aws.TimeValue(output.NatGateways[0].CreateTime)
+
+	case "logs:log-group":
+		output, err := s.logs.DescribeLogGroups(&cloudwatchlogs.DescribeLogGroupsInput{LogGroupNamePrefix: aws.String(r.ID)})
+		if err != nil {
+			return true, err
+		}
+		for _, group := range output.LogGroups {
+			if aws.StringValue(group.LogGroupName) == r.ID {
+				r.Created = time.UnixMilli(aws.Int64Value(group.CreationTime))
+				return true, nil
+			}
+		}
+		return false, nil
+
+	case "secretsmanager:secret":
+		output, err := s.secretsmanager.DescribeSecret(&secretsmanager.DescribeSecretInput{SecretId: aws.String(r.ARN)})
+		if err != nil {
+			return !isAWSErrorCode(err, secretsmanager.ErrCodeResourceNotFoundException), err
+		}
+		if output.DeletedDate != nil {
+			return false, nil
+		}
+		r.Created = aws.TimeValue(output.CreatedDate)
+
+	case "kms:key":
+		output, err := s.kms.DescribeKey(&kms.DescribeKeyInput{KeyId: aws.String(r.ARN)})
+		if err != nil {
+			return !isAWSErrorCode(err, kms.ErrCodeNotFoundException), err
+		}
+		if aws.StringValue(output.KeyMetadata.KeyState) == kms.KeyStatePendingDeletion {
+			return false, nil
+		}
+		r.Created = aws.TimeValue(output.KeyMetadata.CreationDate)
+	}
+
+	return true, nil
+}
+
+// splitECSService parses the "<cluster>/<service>" ID of an ECS service ARN (long ARN format)
+func splitECSService(id string) (cluster, service string, err error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("unexpected ECS service ARN format %q (long ARN format required)", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+// splitSchedule parses the "<group>/<name>" ID of a schedule ARN
+func splitSchedule(id string) (group, name string, err error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("unexpected schedule ARN format %q", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+func isAWSErrorCode(err error, codes ...string) bool {
+	aerr, ok := err.(awserr.Error)
+	if !ok {
+		return false
+	}
+	for _, code := range codes {
+		if aerr.Code() == code {
+			return true
+		}
+	}
+	return false
+}
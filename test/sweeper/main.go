@@ -0,0 +1,120 @@
+// Command sweeper deletes orphaned Terratest resources (fixtures and module runs) by tag.
+//
+// Every resource created by the suite is tagged ManagedBy=terratest and TestName=<run name>.
+// The sweeper lists them through the Resource Groups Tagging API (and the IAM API for roles),
+// groups them by TestName and deletes, in dependency order, the groups older than the TTL.
+// The shared state backend (S3 bucket and DynamoDB table) has no TestName tag and is never touched.
+//
+// Usage:
+//
+//	go run ./sweeper -region us-west-2 -ttl 4h -dry-run
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+func main() {
+	region := flag.String("region", defaultRegion(), "AWS region to sweep")
+	ttl := flag.Duration("ttl", 4*time.Hour, "Only delete test runs older than this (0 deletes every run, including ones of unknown age)")
+	dryRun := flag.Bool("dry-run", false, "Report what would be deleted without deleting anything")
+	testName := flag.String("test-name", "", "Only sweep this test run: TestName equal to it, or to <test-name>-<scenario>")
+	flag.Parse()
+
+	fmt.Printf("🧹 Sweeping Terratest resources in %s (ttl=%s, dry-run=%t)\n", *region, *ttl, *dryRun)
+
+	s, err := newSweeper(*region)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	resources, err := s.inventory(*testName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to list tagged resources: %v\n", err)
+		os.Exit(1)
+	}
+
+	expired := expiredResources(resources, *ttl, time.Now())
+	if len(expired) == 0 {
+		fmt.Println("✅ No orphaned resources found")
+		return
+	}
+
+	if *dryRun {
+		fmt.Printf("📋 Dry run: %d resources would be deleted\n", len(expired))
+		for _, r := range expired {
+			fmt.Printf("   %-36s %-28s %s\n", r.Kind, r.TestName, r.ARN)
+		}
+		return
+	}
+
+	if failed := s.sweep(expired); failed > 0 {
+		fmt.Fprintf(os.Stderr, "❌ %d of %d resources could not be deleted, run the sweeper again in a few minutes\n", failed, len(expired))
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Deleted %d orphaned resources\n", len(expired))
+}
+
+func defaultRegion() string {
+	if region := os.Getenv("AWS_DEFAULT_REGION"); region != "" {
+		return region
+	}
+	return "us-west-2"
+}
+
+// expiredResources returns the resources of every test run older than ttl, in deletion order.
+// A run's age is taken from its oldest resource with a known creation time (load balancers,
+// log groups, services...), so resources without a creation date follow the rest of their run.
+// Runs of unknown age are only swept when ttl is 0.
+func expiredResources(resources []*resource, ttl time.Duration, now time.Time) []*resource {
+	oldest := map[string]time.Time{}
+	for _, r := range resources {
+		if r.Created.IsZero() {
+			continue
+		}
+		if created, ok := oldest[r.TestName]; !ok || r.Created.Before(created) {
+			oldest[r.TestName] = r.Created
+		}
+	}
+
+	runs := map[string]bool{}
+	for _, r := range resources {
+		runs[r.TestName] = true
+	}
+	names := make([]string, 0, len(runs))
+	for name := range runs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	expiredRuns := map[string]bool{}
+	for _, name := range names {
+		created, known := oldest[name]
+		switch {
+		case ttl == 0:
+			expiredRuns[name] = true
+		case !known:
+			fmt.Printf("⏭️  %s: age unknown, skipping (use -ttl 0 to sweep it)\n", name)
+		case now.Sub(created) < ttl:
+			fmt.Printf("⏭️  %s: created %s ago, younger than the ttl\n", name, now.Sub(created).Round(time.Minute))
+		default:
+			fmt.Printf("🗑️  %s: created %s ago\n", name, now.Sub(created).Round(time.Minute))
+			expiredRuns[name] = true
+		}
+	}
+
+	var expired []*resource
+	for _, kind := range resourceKinds {
+		for _, r := range resources {
+			if r.Kind == kind.kind && expiredRuns[r.TestName] {
+				expired = append(expired, r)
+			}
+		}
+	}
+	return expired
+}
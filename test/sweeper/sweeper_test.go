@@ -0,0 +1,95 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseARN checks the kind and ID extracted from the ARNs returned by the Tagging API (no AWS credentials needed)
+func TestParseARN(t *testing.T) {
+	testCases := []struct {
+		arn          string
+		expectedKind string
+		expectedID   string
+	}{
+		{"arn:aws:ecs:us-west-2:123456789012:service/terratest-abc-cluster/terratest-abc", "ecs:service", "terratest-abc-cluster/terratest-abc"},
+		{"arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/terratest-abc-alb/0123456789abcdef", "elasticloadbalancing:loadbalancer", "app/terratest-abc-alb/0123456789abcdef"},
+		{"arn:aws:ec2:us-west-2:123456789012:natgateway/nat-0123456789abcdef0", "ec2:natgateway", "nat-0123456789abcdef0"},
+		{"arn:aws:ec2:us-west-2:123456789012:elastic-ip/eipalloc-0123456789abcdef0", "ec2:elastic-ip", "eipalloc-0123456789abcdef0"},
+		{"arn:aws:logs:us-west-2:123456789012:log-group:/ecs/terratest-abc-service:*", "logs:log-group", "/ecs/terratest-abc-service"},
+		{"arn:aws:ssm:us-west-2:123456789012:parameter/ecs/terratest-abc/API_KEY", "ssm:parameter", "ecs/terratest-abc/API_KEY"},
+		{"arn:aws:route53:::hostedzone/Z0123456789ABCDEFGHIJ", "route53:hostedzone", "Z0123456789ABCDEFGHIJ"},
+		{"arn:aws:sns:us-west-2:123456789012:terratest-abc-alarms", "sns:topic", "terratest-abc-alarms"},
+		{"arn:aws:s3:::terratest-abc-env-files-us-west-2-123456789012", "s3:bucket", "terratest-abc-env-files-us-west-2-123456789012"},
+		{"arn:aws:cloudwatch:us-west-2:123456789012:alarm:terratest-abc-service-cpu-high", "cloudwatch:alarm", "terratest-abc-service-cpu-high"},
+		{"arn:aws:cloudwatch::123456789012:dashboard/terratest-abc-service-dashboard", "cloudwatch:dashboard", "terratest-abc-service-dashboard"},
+		{"arn:aws:scheduler:us-west-2:123456789012:schedule/default/terratest-abc-sch-service-report", "scheduler:schedule", "default/terratest-abc-sch-service-report"},
+		{"not-an-arn", "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.arn, func(t *testing.T) {
+			kind, id := parseARN(tc.arn)
+			require.Equal(t, tc.expectedKind, kind)
+			require.Equal(t, tc.expectedID, id)
+		})
+	}
+}
+
+func TestNewResourceSkipsResourcesOutsideATestRun(t *testing.T) {
+	// The state backend is tagged ManagedBy=terratest but has no TestName
+	require.Nil(t, newResource("arn:aws:s3:::terraform-ecs-webapp-test-us-west-2-123456789012", ""))
+	// Kinds the sweeper does not know how to delete are ignored
+	require.Nil(t, newResource("arn:aws:dynamodb:us-west-2:123456789012:table/terraform-ecs-webapp-test-locks", "terratest-abc"))
+
+	r := newResource("arn:aws:ec2:us-west-2:123456789012:vpc/vpc-0123456789abcdef0", "terratest-abc")
+	require.NotNil(t, r)
+	require.Equal(t, "ec2:vpc", r.Kind)
+	require.Equal(t, "vpc-0123456789abcdef0", r.ID)
+}
+
+func TestBelongsToRun(t *testing.T) {
+	require.True(t, belongsToRun("ci-42-1", ""), "Without -test-name every run is swept")
+	require.True(t, belongsToRun("ci-42-1", "ci-42-1"), "Fixtures and main module of the run")
+	require.True(t, belongsToRun("ci-42-1-sch", "ci-42-1"), "Scenario of the run")
+	require.False(t, belongsToRun("ci-42-10", "ci-42-1"), "Another run sharing the prefix")
+	require.False(t, belongsToRun("terratest-1234", "ci-42-1"), "Another run")
+}
+
+func TestRunOfName(t *testing.T) {
+	runs := map[string]bool{"ci-42-1": true, "ci-42-1-sch": true, "ci-42-10": true}
+	require.Equal(t, "ci-42-1", runOfName("ci-42-1-service-dashboard", runs))
+	require.Equal(t, "ci-42-1-sch", runOfName("ci-42-1-sch-service-report", runs), "The scenario run is the longest prefix")
+	require.Equal(t, "ci-42-10", runOfName("ci-42-10-service-dashboard", runs))
+	require.Equal(t, "", runOfName("production-service-dashboard", runs), "Resources outside the test runs are ignored")
+}
+
+func TestExpiredResources(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	resources := []*resource{
+		{ARN: "vpc-old", Kind: "ec2:vpc", TestName: "terratest-old"},
+		{ARN: "lb-old", Kind: "elasticloadbalancing:loadbalancer", TestName: "terratest-old", Created: now.Add(-3 * time.Hour)},
+		{ARN: "svc-old", Kind: "ecs:service", TestName: "terratest-old", Created: now.Add(-30 * time.Minute)},
+		{ARN: "vpc-new", Kind: "ec2:vpc", TestName: "terratest-new"},
+		{ARN: "lb-new", Kind: "elasticloadbalancing:loadbalancer", TestName: "terratest-new", Created: now.Add(-time.Hour)},
+		{ARN: "sg-unknown", Kind: "ec2:security-group", TestName: "terratest-unknown"},
+	}
+
+	arns := func(resources []*resource) []string {
+		var result []string
+		for _, r := range resources {
+			result = append(result, r.ARN)
+		}
+		return result
+	}
+
+	// The run age comes from its oldest resource, and the result is in deletion order
+	require.Equal(t, []string{"svc-old", "lb-old", "vpc-old"}, arns(expiredResources(resources, 2*time.Hour, now)))
+
+	// ttl 0 sweeps every run, including the one of unknown age
+	require.Equal(t,
+		[]string{"svc-old", "lb-old", "lb-new", "sg-unknown", "vpc-old", "vpc-new"},
+		arns(expiredResources(resources, 0, now)))
+}
@@ -1,9 +1,20 @@
 package test
 
 import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/terraform"
 	"github.com/stretchr/testify/require"
 )
@@ -25,4 +36,339 @@ func testTargetGroup(t *testing.T, moduleOptions *terraform.Options, infraOutput
 	// Verify Target Group name contains service name
 	serviceName := terraform.Output(t, moduleOptions, "service_name")
 	require.Contains(t, strings.ToLower(targetGroupARN), strings.ToLower(serviceName))
+
+	// Verify protocol, protocol version and health check match the module configuration
+	expectedProtocol := "HTTP"
+	if protocol, ok := moduleOptions.Vars["target_group_protocol"].(string); ok {
+		expectedProtocol = protocol
+	}
+	expectedProtocolVersion := "HTTP1"
+	if protocolVersion, ok := moduleOptions.Vars["target_group_protocol_version"].(string); ok {
+		expectedProtocolVersion = protocolVersion
+	}
+	healthCheck := moduleOptions.Vars["health_check"].(map[string]interface{})
+
+	elbClient := newELBv2Client(t, infraOutputs.AWSRegion)
+	targetGroups, err := elbClient.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{
+		TargetGroupArns: []*string{aws.String(targetGroupARN)},
+	})
+	require.NoError(t, err)
+	require.Len(t, targetGroups.TargetGroups, 1)
+	targetGroup := targetGroups.TargetGroups[0]
+
+	t.Logf("🎯 Verifying target group configuration...")
+	t.Logf("   Protocol: %s (expected: %s)", aws.StringValue(targetGroup.Protocol), expectedProtocol)
+	require.Equal(t, expectedProtocol, aws.StringValue(targetGroup.Protocol))
+	t.Logf("   Protocol Version: %s (expected: %s)", aws.StringValue(targetGroup.ProtocolVersion), expectedProtocolVersion)
+	require.Equal(t, expectedProtocolVersion, aws.StringValue(targetGroup.ProtocolVersion))
+	require.Equal(t, "ip", aws.StringValue(targetGroup.TargetType))
+	require.Equal(t, healthCheck["path"], aws.StringValue(targetGroup.HealthCheckPath))
+
+	require.NotNil(t, targetGroup.Matcher)
+	if expectedProtocolVersion == "GRPC" {
+		t.Logf("   gRPC Matcher: %s (expected: %s)", aws.StringValue(targetGroup.Matcher.GrpcCode), healthCheck["matcher"])
+		require.Equal(t, healthCheck["matcher"], aws.StringValue(targetGroup.Matcher.GrpcCode))
+	} else {
+		t.Logf("   HTTP Matcher: %s (expected: %s)", aws.StringValue(targetGroup.Matcher.HttpCode), healthCheck["matcher"])
+		require.Equal(t, healthCheck["matcher"], aws.StringValue(targetGroup.Matcher.HttpCode))
+	}
+
+	// Verify stickiness, slow start and load balancing algorithm
+	t.Logf("⚙️  Verifying target group attributes...")
+	attributesOutput, err := elbClient.DescribeTargetGroupAttributes(&elbv2.DescribeTargetGroupAttributesInput{
+		TargetGroupArn: aws.String(targetGroupARN),
+	})
+	require.NoError(t, err)
+	attributes := make(map[string]string)
+	for _, attribute := range attributesOutput.Attributes {
+		attributes[aws.StringValue(attribute.Key)] = aws.StringValue(attribute.Value)
+	}
+	for key, expected := range expectedTargetGroupAttributes(moduleOptions.Vars) {
+		t.Logf("   %s: %s (expected: %s)", key, attributes[key], expected)
+		require.Equal(t, expected, attributes[key], "Target group attribute %s should match target_group_config", key)
+	}
+}
+
+// expectedTargetGroupAttributes returns the target group attributes the module should set from target_group_config
+// Keys follow DescribeTargetGroupAttributes naming
+func expectedTargetGroupAttributes(vars map[string]interface{}) map[string]string {
+	config, _ := vars["target_group_config"].(map[string]interface{})
+
+	slowStart := 0
+	if value, ok := config["slow_start"].(int); ok {
+		slowStart = value
+	}
+	algorithm := "round_robin"
+	if value, ok := config["load_balancing_algorithm_type"].(string); ok {
+		algorithm = value
+	}
+
+	expected := map[string]string{
+		"slow_start.duration_seconds":   fmt.Sprintf("%d", slowStart),
+		"load_balancing.algorithm.type": algorithm,
+		"stickiness.enabled":            "false",
+	}
+	if mitigation, ok := config["load_balancing_anomaly_mitigation"].(string); ok {
+		expected["load_balancing.algorithm.anomaly_mitigation"] = mitigation
+	}
+
+	if stickiness, ok := config["stickiness"].(map[string]interface{}); ok {
+		stickinessType := stickiness["type"].(string)
+		duration := 86400
+		if value, ok := stickiness["cookie_duration"].(int); ok {
+			duration = value
+		}
+		expected["stickiness.enabled"] = "true"
+		expected["stickiness.type"] = stickinessType
+		expected[fmt.Sprintf("stickiness.%s.duration_seconds", stickinessType)] = fmt.Sprintf("%d", duration)
+		if cookieName, ok := stickiness["cookie_name"].(string); ok {
+			expected["stickiness.app_cookie.cookie_name"] = cookieName
+		}
+	}
+	return expected
+}
+
+func testTargetGroupConfig(t *testing.T, infraOutputs *InfrastructureOutputs, testName string) {
+	if infraOutputs.ALBLoadBalancerARN == "" {
+		t.Logf("⏭️  Skipping target group config test (ALB not configured)")
+		return
+	}
+
+	scenarioName := fmt.Sprintf("%s-tgc", testName)
+
+	// The main run uses the defaults (no stickiness, no slow start, round_robin), this scenario every option
+	moduleOptions, err := deployModuleScenario(t, infraOutputs, scenarioName, func(vars map[string]interface{}) {
+		vars["target_group_config"] = map[string]interface{}{
+			"slow_start": 30,
+			"stickiness": map[string]interface{}{
+				"type":            "lb_cookie",
+				"cookie_duration": 3600,
+			},
+		}
+	})
+	require.NoError(t, err, "Scenario with target_group_config should apply")
+	testTargetGroup(t, moduleOptions, infraOutputs)
+
+	// The target group attributes are updated in place
+	t.Logf("🔄 Switching to weighted_random and app_cookie stickiness...")
+	moduleOptions.Vars["target_group_config"] = map[string]interface{}{
+		"load_balancing_algorithm_type":     "weighted_random",
+		"load_balancing_anomaly_mitigation": "on",
+		"stickiness": map[string]interface{}{
+			"type":            "app_cookie",
+			"cookie_name":     "SESSIONID",
+			"cookie_duration": 600,
+		},
+	}
+	_, err = terraform.ApplyE(t, moduleOptions)
+	require.NoError(t, err, "Changing target_group_config should apply")
+	require.NoError(t, checkEmptyPlan(t, moduleOptions))
+	testTargetGroup(t, moduleOptions, infraOutputs)
+
+	// Removing the stickiness must disable it on AWS, not just stop managing it
+	t.Logf("🔄 Removing target_group_config...")
+	delete(moduleOptions.Vars, "target_group_config")
+	_, err = terraform.ApplyE(t, moduleOptions)
+	require.NoError(t, err, "Removing target_group_config should apply")
+	require.NoError(t, checkEmptyPlan(t, moduleOptions))
+	testTargetGroup(t, moduleOptions, infraOutputs)
+}
+
+func testGRPCTargetGroup(t *testing.T, infraOutputs *InfrastructureOutputs, testName string) {
+	if infraOutputs.ALBHTTPSListenerARN == "" {
+		t.Logf("⏭️  Skipping gRPC target group test (ALB HTTPS listener not configured)")
+		return
+	}
+
+	scenarioName := fmt.Sprintf("%s-grpc", testName)
+
+	// agnhost grpc-health-checking implements grpc.health.v1.Health and answers SERVING: with matcher "0" (OK)
+	// the targets only become healthy if the ALB really calls Health/Check over gRPC (UNIMPLEMENTED is 12)
+	grpcImage := "registry.k8s.io/e2e-test-images/agnhost"
+	if image := os.Getenv("GRPC_TEST_IMAGE"); image != "" {
+		grpcImage = image
+	}
+	grpcPort := 5000
+
+	moduleOptions, err := deployModuleScenario(t, infraOutputs, scenarioName, func(vars map[string]interface{}) {
+		vars["docker_image"] = grpcImage
+		vars["image_tag"] = "2.53"
+		vars["resolve_image_digest"] = false
+		vars["container_port"] = grpcPort
+		// The image entrypoint is /agnhost, the command selects the subcommand
+		vars["container_command"] = []string{"grpc-health-checking", fmt.Sprintf("--port=%d", grpcPort)}
+		vars["alb_listener_arn"] = infraOutputs.ALBHTTPSListenerARN
+		vars["target_group_protocol_version"] = "GRPC"
+		// gRPC requests use the method as path: route the health service to this scenario on the HTTPS listener
+		vars["listener_rules"] = []map[string]interface{}{
+			{
+				"priority":      nextScenarioListenerPriority(),
+				"path_patterns": []string{"/grpc.health.v1.Health/*"},
+			},
+		}
+		vars["health_check"] = map[string]interface{}{
+			"path":                "/grpc.health.v1.Health/Check",
+			"interval":            10,
+			"timeout":             5,
+			"healthy_threshold":   2,
+			"unhealthy_threshold": 2,
+			"matcher":             "0",
+		}
+	})
+	require.NoError(t, err, "Scenario with a gRPC target group should apply")
+
+	testTargetGroup(t, moduleOptions, infraOutputs)
+
+	if isLocalStack() {
+		t.Logf("⏭️  Skipping gRPC health checks on LocalStack: the emulator does not health check targets")
+		return
+	}
+
+	targetGroupARN := terraform.Output(t, moduleOptions, "alb_target_group_arn")
+	t.Logf("💚 Waiting for gRPC targets to become healthy...")
+	err = waitForHealthyTargets(t, infraOutputs.AWSRegion, targetGroupARN)
+	require.NoError(t, err, "gRPC targets should become healthy")
+
+	// Client -> HTTPS listener -> GRPC target group -> task
+	loadBalancers, err := newELBv2Client(t, infraOutputs.AWSRegion).DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{
+		LoadBalancerArns: []*string{aws.String(infraOutputs.ALBLoadBalancerARN)},
+	})
+	require.NoError(t, err)
+	require.Len(t, loadBalancers.LoadBalancers, 1)
+	albDNSName := aws.StringValue(loadBalancers.LoadBalancers[0].DNSName)
+
+	t.Logf("📡 Calling grpc.health.v1.Health/Check through https://%s...", albDNSName)
+	_, err = retry.DoWithRetryE(t, "gRPC health check through the ALB", 10, 10*time.Second, func() (string, error) {
+		return "", grpcHealthCheck(fmt.Sprintf("https://%s/grpc.health.v1.Health/Check", albDNSName))
+	})
+	require.NoError(t, err, "The gRPC health check through the HTTPS listener should return SERVING")
+}
+
+// grpcHealthCheck calls grpc.health.v1.Health/Check (empty service: overall server health) over HTTP/2 and
+// returns an error unless the server answers grpc-status 0 with status SERVING
+// The fixture HTTPS listener uses a self-signed certificate, so it is not verified
+func grpcHealthCheck(url string) error {
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true}, //nolint:gosec // self-signed fixture certificate
+			ForceAttemptHTTP2: true,
+		},
+	}
+
+	// gRPC message framing: 1 byte compressed flag + 4 bytes length, then the (empty) HealthCheckRequest
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader([]byte{0, 0, 0, 0, 0}))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.ProtoMajor != 2 {
+		return fmt.Errorf("expected an HTTP/2 response, got %s", resp.Proto)
+	}
+	// grpc-status comes in the trailers, or in the headers for trailers-only responses
+	status := resp.Trailer.Get("Grpc-Status")
+	if status == "" {
+		status = resp.Header.Get("Grpc-Status")
+	}
+	if resp.StatusCode != http.StatusOK || status != "0" {
+		return fmt.Errorf("HTTP %d, grpc-status %q, grpc-message %q", resp.StatusCode, status, resp.Trailer.Get("Grpc-Message"))
+	}
+
+	// HealthCheckResponse{status: SERVING}: field 1, varint 1
+	serving := []byte{0, 0, 0, 0, 2, 0x08, 0x01}
+	if !bytes.Equal(body, serving) {
+		return fmt.Errorf("expected a SERVING HealthCheckResponse, got %x", body)
+	}
+	return nil
+}
+
+func testMultiplePorts(t *testing.T, infraOutputs *InfrastructureOutputs, testName string) {
+	if infraOutputs.ALBLoadBalancerARN == "" {
+		t.Logf("⏭️  Skipping multiple ports test (ALB not configured)")
+		return
+	}
+
+	scenarioName := fmt.Sprintf("%s-mp", testName)
+	adminPort := 8080
+
+	moduleOptions, err := deployModuleScenario(t, infraOutputs, scenarioName, func(vars map[string]interface{}) {
+		// nginx only listens on 80 by default, add an admin server on 8080
+		vars["container_command"] = []string{
+			"sh", "-c", fmt.Sprintf("echo 'server { listen %d; location / { return 200 admin; } }' > /etc/nginx/conf.d/admin.conf && exec nginx -g 'daemon off;'", adminPort),
+		}
+		vars["additional_ports"] = []map[string]interface{}{
+			{
+				"name": "adm",
+				"port": adminPort,
+				"target_group": map[string]interface{}{
+					"listener_rules": []map[string]interface{}{
+						{
+							"priority":      nextScenarioListenerPriority(),
+							"path_patterns": []string{fmt.Sprintf("/%s-adm/*", scenarioName)},
+						},
+					},
+				},
+			},
+		}
+	})
+	require.NoError(t, err, "Scenario with additional_ports should apply")
+
+	clusterName := terraform.Output(t, moduleOptions, "cluster_name")
+	serviceName := terraform.Output(t, moduleOptions, "service_name")
+	mainTargetGroupARN := terraform.Output(t, moduleOptions, "alb_target_group_arn")
+	targetGroupARNs := terraform.OutputMap(t, moduleOptions, "alb_target_group_arns")
+
+	// Verify one target group per exposed port
+	t.Logf("🎯 Verifying target group ARNs output...")
+	t.Logf("   Target Groups: %v", targetGroupARNs)
+	require.Len(t, targetGroupARNs, 2)
+	require.Equal(t, mainTargetGroupARN, targetGroupARNs["main"])
+	require.NotEmpty(t, targetGroupARNs["adm"])
+	require.NotEqual(t, targetGroupARNs["main"], targetGroupARNs["adm"])
+
+	// Verify the service registers each port in its target group
+	t.Logf("⚖️  Verifying load balancer blocks...")
+	ecsClient := newECSClient(t, infraOutputs.AWSRegion)
+	service, err := ecsClient.DescribeServices(&ecs.DescribeServicesInput{
+		Cluster:  aws.String(clusterName),
+		Services: []*string{aws.String(serviceName)},
+	})
+	require.NoError(t, err)
+	require.Len(t, service.Services, 1)
+
+	portsByTargetGroup := make(map[string]int64)
+	for _, loadBalancer := range service.Services[0].LoadBalancers {
+		portsByTargetGroup[aws.StringValue(loadBalancer.TargetGroupArn)] = aws.Int64Value(loadBalancer.ContainerPort)
+		require.Equal(t, serviceName, aws.StringValue(loadBalancer.ContainerName))
+	}
+	t.Logf("   Container ports by target group: %v", portsByTargetGroup)
+	require.Len(t, portsByTargetGroup, 2)
+	require.Equal(t, int64(80), portsByTargetGroup[targetGroupARNs["main"]])
+	require.Equal(t, int64(adminPort), portsByTargetGroup[targetGroupARNs["adm"]])
+
+	// Verify the task definition exposes both ports
+	taskDef, err := ecsClient.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: service.Services[0].TaskDefinition,
+	})
+	require.NoError(t, err)
+	var containerPorts []int64
+	for _, mapping := range taskDef.TaskDefinition.ContainerDefinitions[0].PortMappings {
+		containerPorts = append(containerPorts, aws.Int64Value(mapping.ContainerPort))
+	}
+	require.ElementsMatch(t, []int64{80, int64(adminPort)}, containerPorts)
+
+	// The admin port must be reachable from the ALB as well
+	testSecurityGroup(t, moduleOptions, infraOutputs)
 }
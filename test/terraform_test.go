@@ -1,6 +1,7 @@
 package test
 
 import (
+	"os"
 	"testing"
 	"time"
 
@@ -10,8 +11,11 @@ import (
 func TestTerraformModule(t *testing.T) {
 	t.Parallel()
 
-	// Generate unique test name
+	// Generate unique test name, or use TEST_RUN_NAME so CI can sweep exactly this run afterwards
 	testName := sanitizeName(getRandomName("terratest"))
+	if runName := os.Getenv("TEST_RUN_NAME"); runName != "" {
+		testName = sanitizeName(runName)
+	}
 
 	// Setup infrastructure fixtures
 	// This now returns options even if there are errors, so cleanup can always run
@@ -51,6 +55,14 @@ func TestTerraformModule(t *testing.T) {
 		// The test will fail naturally if subsequent operations fail
 	} else {
 		t.Logf("✅ Module applied successfully")
+
+		// The module must converge: a second plan right after the apply has to be a no-op
+		t.Logf("🔁 Checking that a second plan is empty...")
+		if err := checkEmptyPlan(t, moduleOptions); err != nil {
+			t.Errorf("❌ Module does not converge: %v", err)
+		} else {
+			t.Logf("✅ Second plan is empty")
+		}
 	}
 
 	// Wait for ECS service to stabilize
@@ -75,13 +87,102 @@ func TestTerraformModule(t *testing.T) {
 		testIAM(t, moduleOptions, infraOutputs)
 	})
 
+	t.Run("Long Service Name", func(t *testing.T) {
+		testLongServiceNamePlan(t, infraOutputs, testName)
+	})
+
+	t.Run("Repository Credentials", func(t *testing.T) {
+		testRepositoryCredentialsPlan(t, infraOutputs, testName)
+	})
+
 	t.Run("Security Group", func(t *testing.T) {
 		testSecurityGroup(t, moduleOptions, infraOutputs)
 	})
 
+	t.Run("Alarms", func(t *testing.T) {
+		testAlarms(t, moduleOptions, infraOutputs)
+	})
+
 	t.Run("Outputs", func(t *testing.T) {
 		testOutputs(t, moduleOptions, infraOutputs)
 	})
+
+	// Scenarios that need a different module configuration deploy their own copy of the module
+	t.Run("Environment Files", func(t *testing.T) {
+		testEnvironmentFiles(t, infraOutputs, testName)
+	})
+
+	t.Run("Secret Injection", func(t *testing.T) {
+		testSecretInjection(t, infraOutputs, testName)
+	})
+
+	t.Run("Security Group Custom Rules", func(t *testing.T) {
+		testSecurityGroupCustomRules(t, infraOutputs, testName)
+	})
+
+	t.Run("Public Subnet Mode", func(t *testing.T) {
+		testPublicSubnetMode(t, infraOutputs, testName)
+	})
+
+	t.Run("Module Log Group", func(t *testing.T) {
+		testModuleLogGroup(t, infraOutputs, testName)
+	})
+
+	t.Run("Multiple Ports", func(t *testing.T) {
+		testMultiplePorts(t, infraOutputs, testName)
+	})
+
+	t.Run("gRPC Target Group", func(t *testing.T) {
+		testGRPCTargetGroup(t, infraOutputs, testName)
+	})
+
+	t.Run("Target Group Config", func(t *testing.T) {
+		testTargetGroupConfig(t, infraOutputs, testName)
+	})
+
+	t.Run("NLB Mode", func(t *testing.T) {
+		testNLBMode(t, infraOutputs, testName)
+	})
+
+	t.Run("Route 53 Aliases", func(t *testing.T) {
+		testRoute53Aliases(t, infraOutputs, testName)
+	})
+
+	t.Run("Deployment Alarms Rollback", func(t *testing.T) {
+		testDeploymentAlarmsRollback(t, infraOutputs, testName)
+	})
+
+	t.Run("Circuit Breaker Rollback", func(t *testing.T) {
+		testCircuitBreakerRollback(t, infraOutputs, testName)
+	})
+
+	t.Run("Scheduled Tasks", func(t *testing.T) {
+		testScheduledTasks(t, infraOutputs, testName)
+	})
+
+	t.Run("Availability Zone Spread", func(t *testing.T) {
+		testAvailabilityZoneSpread(t, infraOutputs, testName)
+	})
+
+	t.Run("Desired Count Managed By Autoscaling", func(t *testing.T) {
+		testDesiredCountManagedByAutoscaling(t, infraOutputs, testName)
+	})
+
+	t.Run("Autoscaled Service Matches Service", func(t *testing.T) {
+		testAutoscaledServiceMatchesService(t, infraOutputs, testName)
+	})
+
+	t.Run("Upgrade Path", func(t *testing.T) {
+		testUpgradePath(t, infraOutputs, testName)
+	})
+
+	t.Run("Migration Task", func(t *testing.T) {
+		testMigrationTask(t, infraOutputs, testName)
+	})
+
+	t.Run("Failed Migration Task", func(t *testing.T) {
+		testFailedMigrationTask(t, infraOutputs, testName)
+	})
 }
 
 // Helper function to wait for ECS service to be stable
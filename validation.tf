@@ -19,8 +19,8 @@ resource "terraform_data" "validation" {
     }
 
     precondition {
-      condition     = var.alb_load_balancer_arn != null || var.service_discovery != null
-      error_message = "Either alb_load_balancer_arn or service_discovery must be provided for service access"
+      condition     = var.alb_load_balancer_arn != null || var.nlb != null || var.service_discovery != null
+      error_message = "Either alb_load_balancer_arn, nlb or service_discovery must be provided for service access"
     }
 
     precondition {
@@ -32,5 +32,77 @@ resource "terraform_data" "validation" {
       condition     = var.alb_listener_arn == null || var.alb_load_balancer_arn != null
       error_message = "alb_listener_arn must be provided when alb_load_balancer_arn is provided"
     }
+
+    precondition {
+      condition     = var.alb_load_balancer_arn != null || alltrue([for port in var.additional_ports : port.target_group == null])
+      error_message = "additional_ports with target_group require alb_load_balancer_arn"
+    }
+
+    precondition {
+      condition     = length(local.target_groups) + (var.nlb != null ? 1 : 0) <= 5
+      error_message = "ECS services support up to 5 target groups: the ALB target group of container_port, one per additional_ports with target_group and the NLB target group (nlb) count towards the limit"
+    }
+
+    precondition {
+      condition     = var.route53 == null || var.alb_load_balancer_arn != null
+      error_message = "route53 requires alb_load_balancer_arn (alias records point at the ALB)"
+    }
+
+    # Las alarmas del módulo usadas en deployment_alarms deben existir (alarms configurado y, para las del ALB, alb_load_balancer_arn)
+    precondition {
+      condition     = var.deployment_alarms == null || try(alltrue([for name in var.deployment_alarms.module_alarms : contains(keys(local.module_alarm_names), name)]), false)
+      error_message = "deployment_alarms.module_alarms references alarms that the module does not create: enable them in alarms (ALB alarms also require alb_load_balancer_arn)"
+    }
+
+    # AWS rechaza target groups cuyo nombre empieza con "internal-"
+    precondition {
+      condition     = (var.alb_load_balancer_arn == null && var.nlb == null) || !startswith(lower(local.name_base), "internal-")
+      error_message = "service_name (or name_prefix) cannot start with 'internal-' when target groups are created, set a different name_prefix"
+    }
+
+    precondition {
+      condition     = var.nlb == null || try(var.nlb.security_group_id != null || length(var.nlb.client_cidr_blocks) > 0, false)
+      error_message = "nlb requires security_group_id or client_cidr_blocks so the tasks accept traffic from the NLB"
+    }
+
+    # gRPC target groups match gRPC status codes (0-99), HTTP1/HTTP2 target groups match HTTP codes (200-499)
+    precondition {
+      condition = alltrue([
+        for tg in values(local.target_groups) :
+        tg.protocol_version == "GRPC" ?
+        can(regex("^[0-9]{1,2}(-[0-9]{1,2})?(,[0-9]{1,2}(-[0-9]{1,2})?)*$", tg.health_check.matcher)) :
+        can(regex("^[2-4][0-9]{2}(-[2-4][0-9]{2})?(,[2-4][0-9]{2}(-[2-4][0-9]{2})?)*$", tg.health_check.matcher))
+      ])
+      error_message = "health_check.matcher must use gRPC status codes (0-99, e.g. '0' or '0-99') for GRPC target groups and HTTP codes (200-499, e.g. '200-399') otherwise"
+    }
+
+    precondition {
+      condition = !var.assign_public_ip || alltrue([
+        for rule in var.security_group_ingress_rules :
+        length(setintersection(rule.cidr_blocks != null ? rule.cidr_blocks : [], ["0.0.0.0/0", "::/0"])) == 0
+      ])
+      error_message = "security_group_ingress_rules cannot open the container to 0.0.0.0/0 or ::/0 when assign_public_ip = true, because the tasks would be reachable from the Internet on their public IP"
+    }
+
+    precondition {
+      condition     = var.min_availability_zones <= 1 || length(distinct(data.aws_subnet.service[*].availability_zone)) >= var.min_availability_zones
+      error_message = "subnet_ids must span at least min_availability_zones distinct Availability Zones"
+    }
+  }
+}
+
+# Non-blocking warnings (reported by terraform plan/apply without failing)
+
+check "multi_az_spread" {
+  assert {
+    condition     = var.autoscaling_config.min_capacity < 2 || length(distinct(var.subnet_ids)) >= 2
+    error_message = "autoscaling_config.min_capacity is 2 or more but subnet_ids has a single subnet: all tasks will run in the same Availability Zone. Add subnets in other AZs and consider min_availability_zones = 2."
+  }
+}
+
+check "public_ip_ingress" {
+  assert {
+    condition     = !var.assign_public_ip || length([for rule in var.security_group_ingress_rules : rule if rule.cidr_blocks != null]) == 0
+    error_message = "assign_public_ip = true: the cidr_blocks in security_group_ingress_rules can reach the tasks directly on their public IP, bypassing the ALB. Prefer source_security_group_id or prefix_list_ids."
   }
 }
@@ -0,0 +1,12 @@
+terraform {
+  # terraform_data (1.4) y bloques check (1.5)
+  required_version = ">= 1.5"
+
+  required_providers {
+    # 5.81.0: availability_zone_rebalancing en aws_ecs_service
+    aws = {
+      source  = "hashicorp/aws"
+      version = ">= 5.81.0"
+    }
+  }
+}